@@ -17,9 +17,12 @@ import (
 	"github.com/akgarg0472/urlshortener-auth-service/discovery"
 	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
 	"github.com/akgarg0472/urlshortener-auth-service/internal/metrics"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/middleware"
 	"github.com/akgarg0472/urlshortener-auth-service/internal/router"
 	oauth_service "github.com/akgarg0472/urlshortener-auth-service/internal/service/auth/oauth"
 	kafka_service "github.com/akgarg0472/urlshortener-auth-service/internal/service/kafka"
+	token_service "github.com/akgarg0472/urlshortener-auth-service/internal/service/token"
+	user_service "github.com/akgarg0472/urlshortener-auth-service/internal/service/user"
 	"github.com/akgarg0472/urlshortener-auth-service/utils"
 )
 
@@ -33,7 +36,13 @@ var (
 func init() {
 	database.InitDB()
 	oauth_service.InitOAuthProviders()
+	oauth_service.InitOAuthStatePurger()
 	kafka_service.InitKafka()
+	token_service.InitRevokedTokenPurger()
+	token_service.InitKeyRingRefresher()
+	user_service.InitDeletedUserPurger()
+	user_service.InitDataExportPurger()
+	middleware.InitRateLimitBackend()
 }
 
 func main() {
@@ -106,6 +115,11 @@ func loadRoutersV1() *chi.Mux {
 
 	r.Mount("/api/v1/auth", router.AuthRouterV1())
 	r.Mount("/api/v1/auth/oauth", router.OAuthRouterV1())
+	r.Mount("/api/v1/auth/admin/keys", router.SigningKeyRouterV1())
+	r.Mount("/api/v1/auth/admin/users", router.AdminUserRouterV1())
+	r.Mount("/api/v1/users", router.UserRouterV1())
+	r.Mount("/oauth2", router.IntrospectionRouterV1())
+	r.Mount("/.well-known", router.WellKnownRouterV1())
 	r.Mount("/", router.PingRouterV1())
 	r.Mount("/admin", router.DiscoveryRouterV1())
 	r.Handle("/prometheus/metrics", metrics.MetricsHandler())
@@ -10,3 +10,5 @@ const ErrorMessageLogKey string = "error_message"
 const StatusCodeLogKey string = "status_code"
 const ServiceHostLogKey string = "host"
 const ServicePortLogKey string = "port"
+const AccountThrottleKeyPrefix string = "account:"
+const IpThrottleKeyPrefix string = "ip:"
@@ -1,20 +1,115 @@
 package constants
 
 type OAuthProvider string
+type OAuthProviderType string
 type UserEntityLoginType string
 type NotificationType string
+type SessionLoginMethod string
+type ActionTokenType string
+type AuditEvent string
+type DataExportFormat string
+type DataExportStatus string
+type OAuthStatePurpose string
 
 const (
 	OauthProviderGoogle OAuthProvider = "google"
 	OauthProviderGithub OAuthProvider = "github"
 )
 
+const (
+	// OAuthProviderTypeOAuth2 providers have their own integration in code, e.g. Google and GitHub
+	OAuthProviderTypeOAuth2 OAuthProviderType = "oauth2"
+	// OAuthProviderTypeOidc providers are integrated generically using OpenID Connect discovery
+	OAuthProviderTypeOidc OAuthProviderType = "oidc"
+)
+
+const (
+	OAuthStatePurposeLogin            OAuthStatePurpose = "login"
+	OAuthStatePurposeLink             OAuthStatePurpose = "link"
+	OAuthStatePurposeReauthentication OAuthStatePurpose = "reauthentication"
+)
+
 const (
 	UserEntityLoginTypeEmailAndPassword UserEntityLoginType = "email_pass"
 	UserEntityLoginTypeOauthAndOtp      UserEntityLoginType = "oauth_otp"
 	UserEntityLoginTypeOauthOnly        UserEntityLoginType = "oauth_only"
+	UserEntityLoginTypeOauthAndPassword UserEntityLoginType = "oauth_pass"
 )
 
+// AllowsPassword reports whether users of the login type can log in using their email and password
+func (t UserEntityLoginType) AllowsPassword() bool {
+	return t == UserEntityLoginTypeEmailAndPassword || t == UserEntityLoginTypeOauthAndPassword
+}
+
+// AllowsOtp reports whether users of the login type can log in using a code emailed to them
+func (t UserEntityLoginType) AllowsOtp() bool {
+	return t == UserEntityLoginTypeOauthAndOtp || t == UserEntityLoginTypeOauthAndPassword
+}
+
+// AllowsMagicLink reports whether users of the login type can log in using a link emailed to them
+func (t UserEntityLoginType) AllowsMagicLink() bool {
+	return t.AllowsPassword() || t.AllowsOtp()
+}
+
+// WithPassword returns the login type of the user after a password is added to the account
+func (t UserEntityLoginType) WithPassword() UserEntityLoginType {
+	if t == UserEntityLoginTypeOauthAndOtp {
+		return UserEntityLoginTypeOauthAndPassword
+	}
+	return t
+}
+
 const (
 	NotificationTypeEmail NotificationType = "EMAIL"
 )
+
+const (
+	SessionLoginMethodEmailPassword SessionLoginMethod = "email_pass"
+	SessionLoginMethodGithub        SessionLoginMethod = "github"
+	SessionLoginMethodGoogle        SessionLoginMethod = "google"
+	SessionLoginMethodOidc          SessionLoginMethod = "oidc"
+	SessionLoginMethodOtp           SessionLoginMethod = "otp"
+	SessionLoginMethodMagicLink     SessionLoginMethod = "magic_link"
+)
+
+const (
+	ActionTokenTypeMagicLink         ActionTokenType = "magic_link"
+	ActionTokenTypeEmailVerification ActionTokenType = "email_verification"
+	ActionTokenTypePasswordReset     ActionTokenType = "password_reset"
+	ActionTokenTypeAccountRestore    ActionTokenType = "account_restore"
+	ActionTokenTypeDataExport        ActionTokenType = "data_export"
+	ActionTokenTypeEmailChange       ActionTokenType = "email_change"
+	ActionTokenTypeEmailChangeCancel ActionTokenType = "email_change_cancel"
+)
+
+const (
+	AuditEventPasswordResetRequested AuditEvent = "password_reset_requested"
+	AuditEventPasswordResetVerified  AuditEvent = "password_reset_verified"
+	AuditEventPasswordResetCompleted AuditEvent = "password_reset_completed"
+	AuditEventPasswordResetFailed    AuditEvent = "password_reset_failed"
+	AuditEventPasswordSetupRequested AuditEvent = "password_setup_requested"
+	AuditEventPasswordAdded          AuditEvent = "password_added"
+	AuditEventIdentityLinked         AuditEvent = "identity_linked"
+	AuditEventIdentityUnlinked       AuditEvent = "identity_unlinked"
+	AuditEventProfileUpdated         AuditEvent = "profile_updated"
+	AuditEventAccountDeleted         AuditEvent = "account_deleted"
+	AuditEventAccountRestored        AuditEvent = "account_restored"
+	AuditEventAccountPurged          AuditEvent = "account_purged"
+	AuditEventDataExported           AuditEvent = "data_exported"
+	AuditEventDataExportRequested    AuditEvent = "data_export_requested"
+	AuditEventDataExportDownloaded   AuditEvent = "data_export_downloaded"
+	AuditEventEmailChangeRequested   AuditEvent = "email_change_requested"
+	AuditEventEmailChanged           AuditEvent = "email_changed"
+	AuditEventEmailChangeCancelled   AuditEvent = "email_change_cancelled"
+)
+
+const (
+	DataExportFormatJson DataExportFormat = "json"
+	DataExportFormatZip  DataExportFormat = "zip"
+)
+
+const (
+	DataExportStatusPending DataExportStatus = "pending"
+	DataExportStatusReady   DataExportStatus = "ready"
+	DataExportStatusFailed  DataExportStatus = "failed"
+)
@@ -65,35 +65,141 @@ func InitDB() {
 	})
 }
 
+type schema interface {
+	TableName() string
+}
+
 func initSchemas() {
-	user := entity.User{}
-	oAuthClient := entity.OAuthProvider{}
+	schemas := []schema{
+		&entity.User{},
+		&entity.OAuthProvider{},
+		&entity.RefreshToken{},
+		&entity.RevokedToken{},
+		&entity.SigningKey{},
+		&entity.Session{},
+		&entity.LoginThrottle{},
+		&entity.UserMfa{},
+		&entity.LoginOtp{},
+		&entity.ActionToken{},
+		&entity.PasswordHistory{},
+		&entity.AuditLog{},
+		&entity.OAuthState{},
+		&entity.UserIdentity{},
+		&entity.DataExport{},
+	}
+
+	// must be checked before the migration adds the column
+	migrateEmailVerified := instance.Migrator().HasTable(&entity.User{}) &&
+		!instance.Migrator().HasColumn(&entity.User{}, "EmailVerified")
+
+	tables := make([]string, 0, len(schemas))
+
+	for _, s := range schemas {
+		err := instance.AutoMigrate(s)
+
+		if err != nil {
+			if logger.IsFatalEnabled() {
+				logger.Fatal("Error initializing database schema",
+					zap.String("table", s.TableName()),
+					zap.Error(err),
+				)
+			}
+			panic(fmt.Sprintf("Error initializing DB schema `%s`: %v", s.TableName(), err))
+		}
+
+		tables = append(tables, s.TableName())
+	}
+
+	if migrateEmailVerified {
+		markExistingUsersEmailVerified()
+	}
+
+	// forgot password tokens used to be stored raw, now only their digests are kept in action_tokens
+	if instance.Migrator().HasColumn(&entity.User{}, "forgot_password_token") {
+		dropUsersColumn("forgot_password_token")
+	}
+
+	// OAuth identities used to be stored on the users, limiting every user to a single provider identity
+	if instance.Migrator().HasColumn(&entity.User{}, "oauth_id") &&
+		instance.Migrator().HasColumn(&entity.User{}, "oauth_provider") {
+		moveOAuthIdentitiesToUserIdentities()
+		dropUsersColumn("oauth_id")
+		dropUsersColumn("oauth_provider")
+	}
+
+	logger.Info("Initialized database schemas successfully",
+		zap.Strings("tables", tables),
+	)
+}
+
+// users registered before email verification was introduced are trusted with their emails
+func markExistingUsersEmailVerified() {
+	timestamp := time.Now().UnixMilli()
 
-	err := instance.AutoMigrate(&user)
+	result := instance.Model(&entity.User{}).Where("email is not null").UpdateColumns(map[string]interface{}{
+		"EmailVerified":   true,
+		"EmailVerifiedAt": timestamp,
+	})
 
-	if err != nil {
+	if result.Error != nil {
 		if logger.IsFatalEnabled() {
-			logger.Fatal("Error initializing database schema",
-				zap.String("table", user.TableName()),
-				zap.Error(err),
+			logger.Fatal("Error marking existing users email verified",
+				zap.Error(result.Error),
 			)
 		}
-		panic(fmt.Sprintf("Error initializing DB schema `%s`: %v", user.TableName(), err))
+		panic(fmt.Sprintf("Error marking existing users email verified: %v", result.Error))
 	}
 
-	err = instance.AutoMigrate(&oAuthClient)
+	if logger.IsInfoEnabled() {
+		logger.Info("Marked existing users email verified",
+			zap.Int64("users", result.RowsAffected),
+		)
+	}
+}
 
-	if err != nil {
+// function to copy the OAuth identity of every user into user_identities, skipping the ones already copied
+func moveOAuthIdentitiesToUserIdentities() {
+	result := instance.Exec(`
+		INSERT INTO user_identities (id, user_id, provider, provider_user_id, email, created_at, last_login_at)
+		SELECT REPLACE(UUID(), '-', ''), u.id, u.oauth_provider, u.oauth_id, u.email, u.created_at, u.last_login_at
+		FROM users u
+		WHERE u.oauth_id IS NOT NULL AND u.oauth_id <> '' AND u.oauth_provider IS NOT NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM user_identities i WHERE i.provider = u.oauth_provider AND i.provider_user_id = u.oauth_id
+		)`)
+
+	if result.Error != nil {
 		if logger.IsFatalEnabled() {
-			logger.Fatal("Error initializing database schema", zap.String("schema", oAuthClient.TableName()), zap.Error(err))
+			logger.Fatal("Error moving OAuth identities to user_identities",
+				zap.Error(result.Error),
+			)
 		}
-		panic(fmt.Sprintf("Error initializing DB schema `%s`: %v", oAuthClient.TableName(), err))
+		panic(fmt.Sprintf("Error moving OAuth identities to user_identities: %v", result.Error))
 	}
 
-	logger.Info("Initialized database schemas successfully",
-		zap.String("user_schema", user.TableName()),
-		zap.String("oauth_client_schema", oAuthClient.TableName()),
-	)
+	if logger.IsInfoEnabled() {
+		logger.Info("Moved OAuth identities to user_identities",
+			zap.Int64("identities", result.RowsAffected),
+		)
+	}
+}
+
+func dropUsersColumn(column string) {
+	if err := instance.Migrator().DropColumn(&entity.User{}, column); err != nil {
+		if logger.IsFatalEnabled() {
+			logger.Fatal("Error dropping users column",
+				zap.String("column", column),
+				zap.Error(err),
+			)
+		}
+		panic(fmt.Sprintf("Error dropping users column `%s`: %v", column, err))
+	}
+
+	if logger.IsInfoEnabled() {
+		logger.Info("Dropped users column",
+			zap.String("column", column),
+		)
+	}
 }
 
 func GetInstance(requestId string, from string) *gorm.DB {
@@ -106,6 +212,11 @@ func GetInstance(requestId string, from string) *gorm.DB {
 	return instance
 }
 
+// SetInstance replaces the connection returned by GetInstance, e.g. by a mocked one in tests
+func SetInstance(db *gorm.DB) {
+	instance = db
+}
+
 func getDatasource() string {
 	dbHost := utils.GetEnvVariable("MYSQL_DB_HOST", "127.0.0.1")
 	dbPort := utils.GetEnvVariable("MYSQL_DB_PORT", "3306")
@@ -0,0 +1,157 @@
+package action_dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+)
+
+func logErrorGettingDBInstance(requestId string) {
+	if logger.IsErrorEnabled() {
+		logger.Error("Error getting DB instance",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+}
+
+// SaveActionToken stores the token and invalidates the unused tokens previously issued to the user for the same
+// action, so only the most recent link keeps working
+func SaveActionToken(requestId string, actionToken *entity.ActionToken) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Saving action token into DB",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("user_id", actionToken.UserId),
+			zap.String("action", string(actionToken.Action)),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "SaveActionToken")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	now := time.Now().UnixMilli()
+	actionToken.CreatedAt = now
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.ActionToken{}).
+			Where("user_id = ? AND action = ? AND used_at IS NULL", actionToken.UserId, actionToken.Action).
+			UpdateColumn("used_at", now)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Create(actionToken).Error
+	})
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error saving action token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
+
+// GetActionTokenByHash returns the token of the action with the given digest, or nil if there is none
+func GetActionTokenByHash(
+	requestId string,
+	action constants.ActionTokenType,
+	tokenHash string,
+) (*entity.ActionToken, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetActionTokenByHash")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var actionToken entity.ActionToken
+
+	result := db.First(&actionToken, "token_hash = ? AND action = ?", tokenHash, action)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		if logger.IsErrorEnabled() {
+			logger.Error("Error fetching action token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return &actionToken, nil
+}
+
+// MarkActionTokenUsed marks the token as used. Returns false if the token was already used by another request
+func MarkActionTokenUsed(requestId string, id string) (bool, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "MarkActionTokenUsed")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	result := db.Model(&entity.ActionToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		UpdateColumn("used_at", time.Now().UnixMilli())
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error marking action token used",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateActionTokens marks the unused tokens issued to the user for the action used, so that their links stop working
+func InvalidateActionTokens(requestId string, userId string, action constants.ActionTokenType) *Models.ErrorResponse {
+	db := MySQL.GetInstance(requestId, "InvalidateActionTokens")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	result := db.Model(&entity.ActionToken{}).
+		Where("user_id = ? AND action = ? AND used_at IS NULL", userId, action).
+		UpdateColumn("used_at", time.Now().UnixMilli())
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error invalidating action tokens",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
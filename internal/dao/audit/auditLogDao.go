@@ -0,0 +1,98 @@
+package audit_dao
+
+import (
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"go.uber.org/zap"
+
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+)
+
+func logErrorGettingDBInstance(requestId string) {
+	if logger.IsErrorEnabled() {
+		logger.Error("Error getting DB instance",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+}
+
+func SaveAuditLog(requestId string, auditLog *entity.AuditLog) *Models.ErrorResponse {
+	db := MySQL.GetInstance(requestId, "SaveAuditLog")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	auditLog.CreatedAt = time.Now().UnixMilli()
+
+	if err := db.Create(auditLog).Error; err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error saving audit log",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("event", string(auditLog.Event)),
+				zap.Error(err),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
+
+// GetAuditLogsByUserId returns the audit logs of the user, most recent first
+func GetAuditLogsByUserId(requestId string, userId string) ([]entity.AuditLog, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetAuditLogsByUserId")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var auditLogs []entity.AuditLog
+
+	result := db.Where("user_id = ?", userId).Order("created_at desc").Find(&auditLogs)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error fetching audit logs of user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return auditLogs, nil
+}
+
+// CountAuditLogsByUserId returns the number of audit logs of the user
+func CountAuditLogsByUserId(requestId string, userId string) (int64, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "CountAuditLogsByUserId")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return 0, utils.InternalServerErrorResponse()
+	}
+
+	var count int64
+
+	result := db.Model(&entity.AuditLog{}).Where("user_id = ?", userId).Count(&count)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error counting audit logs of user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return 0, utils.InternalServerErrorResponse()
+	}
+
+	return count, nil
+}
@@ -2,7 +2,6 @@ package auth_dao
 
 import (
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/akgarg0472/urlshortener-auth-service/constants"
@@ -30,6 +29,22 @@ func logErrorGettingDBInstance(requestId string) {
 	}
 }
 
+func mapUserEntityToModel(dbUser entity.User) Models.User {
+	return Models.User{
+		Id:                dbUser.Id,
+		Name:              dbUser.Name,
+		Email:             utils.GetStringOrNil(dbUser.Email),
+		Password:          utils.GetStringOrNil(dbUser.Password),
+		Scopes:            dbUser.Scopes,
+		LastLoginAt:       utils.GetInt64OrNil(dbUser.LastLoginAt),
+		PasswordChangedAt: utils.GetInt64OrNil(dbUser.LastPasswordChangedAt),
+		TokensRevokedAt:   utils.GetInt64OrNil(dbUser.TokensRevokedAt),
+		IsDeleted:         dbUser.IsDeleted,
+		EmailVerified:     dbUser.EmailVerified,
+		LoginType:         dbUser.UserLoginType,
+	}
+}
+
 func GetUserByEmail(requestId string, identity string) (*Models.User, *Models.ErrorResponse) {
 	if logger.IsInfoEnabled() {
 		logger.Info("Getting user by email",
@@ -47,7 +62,7 @@ func GetUserByEmail(requestId string, identity string) (*Models.User, *Models.Er
 
 	var dbUser entity.User
 
-	result := db.First(&dbUser, "email = ?", identity)
+	result := db.First(&dbUser, "email = ? and is_deleted = ?", identity, false)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -69,20 +84,7 @@ func GetUserByEmail(requestId string, identity string) (*Models.User, *Models.Er
 		return nil, utils.InternalServerErrorResponse()
 	}
 
-	user := Models.User{
-		Id:                  dbUser.Id,
-		Name:                dbUser.Name,
-		Email:               utils.GetStringOrNil(dbUser.Email),
-		Password:            utils.GetStringOrNil(dbUser.Password),
-		Scopes:              dbUser.Scopes,
-		ForgotPasswordToken: utils.GetStringOrNil(dbUser.ForgotPasswordToken),
-		LastLoginAt:         utils.GetInt64OrNil(dbUser.LastLoginAt),
-		PasswordChangedAt:   utils.GetInt64OrNil(dbUser.LastPasswordChangedAt),
-		IsDeleted:           dbUser.IsDeleted,
-		OAuthId:             utils.GetStringOrNil(dbUser.OAuthId),
-		LoginType:           dbUser.UserLoginType,
-		OAuthProvider:       utils.GetStringOrNil(dbUser.OAuthProvider),
-	}
+	user := mapUserEntityToModel(dbUser)
 
 	if logger.IsDebugEnabled() {
 		logger.Debug("Fetched user",
@@ -111,7 +113,7 @@ func GetUserById(requestId string, identity string) (*Models.User, *Models.Error
 
 	var dbUser entity.User
 
-	result := db.First(&dbUser, "id = ?", identity)
+	result := db.First(&dbUser, "id = ? and is_deleted = ?", identity, false)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -134,85 +136,7 @@ func GetUserById(requestId string, identity string) (*Models.User, *Models.Error
 		return nil, utils.InternalServerErrorResponse()
 	}
 
-	user := Models.User{
-		Id:                  dbUser.Id,
-		Name:                dbUser.Name,
-		Email:               utils.GetStringOrNil(dbUser.Email),
-		Password:            utils.GetStringOrNil(dbUser.Password),
-		Scopes:              dbUser.Scopes,
-		ForgotPasswordToken: utils.GetStringOrNil(dbUser.ForgotPasswordToken),
-		LastLoginAt:         utils.GetInt64OrNil(dbUser.LastLoginAt),
-		PasswordChangedAt:   utils.GetInt64OrNil(dbUser.LastPasswordChangedAt),
-		IsDeleted:           dbUser.IsDeleted,
-		OAuthId:             utils.GetStringOrNil(dbUser.OAuthId),
-		LoginType:           dbUser.UserLoginType,
-		OAuthProvider:       utils.GetStringOrNil(dbUser.OAuthProvider),
-	}
-
-	if logger.IsDebugEnabled() {
-		logger.Debug("Fetched user",
-			zap.String(constants.RequestIdLogKey, requestId),
-			zap.Any("user", user),
-		)
-	}
-
-	return &user, nil
-}
-
-func GetUserByOAuthId(requestId string, oAuthId string) (*Models.User, *Models.ErrorResponse) {
-	if logger.IsInfoEnabled() {
-		logger.Info("Getting user by oAuthId",
-			zap.String(constants.RequestIdLogKey, requestId),
-			zap.String("oAuthId", oAuthId),
-		)
-	}
-
-	db := MySQL.GetInstance(requestId, "GetUserByOAuthId")
-
-	if db == nil {
-		logErrorGettingDBInstance(requestId)
-		return nil, utils.InternalServerErrorResponse()
-	}
-
-	var dbUser entity.User
-
-	result := db.First(&dbUser, "oauth_id = ?", oAuthId)
-
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			if logger.IsErrorEnabled() {
-				logger.Error("No user found with oAuthId",
-					zap.String(constants.RequestIdLogKey, requestId),
-					zap.String("oAuthId", oAuthId),
-				)
-			}
-			return nil, utils.GetErrorResponse(fmt.Sprintf("No user found by oAuthId: %s", oAuthId), 404)
-		} else {
-			if logger.IsErrorEnabled() {
-				logger.Error("Error querying user",
-					zap.String(constants.RequestIdLogKey, requestId),
-					zap.Error(result.Error),
-				)
-			}
-		}
-
-		return nil, utils.InternalServerErrorResponse()
-	}
-
-	user := Models.User{
-		Id:                  dbUser.Id,
-		Name:                dbUser.Name,
-		Email:               utils.GetStringOrNil(dbUser.Email),
-		Password:            utils.GetStringOrNil(dbUser.Password),
-		Scopes:              dbUser.Scopes,
-		ForgotPasswordToken: utils.GetStringOrNil(dbUser.ForgotPasswordToken),
-		LastLoginAt:         utils.GetInt64OrNil(dbUser.LastLoginAt),
-		PasswordChangedAt:   utils.GetInt64OrNil(dbUser.LastPasswordChangedAt),
-		IsDeleted:           dbUser.IsDeleted,
-		OAuthId:             *dbUser.OAuthId,
-		OAuthProvider:       *dbUser.OAuthProvider,
-		LoginType:           dbUser.UserLoginType,
-	}
+	user := mapUserEntityToModel(dbUser)
 
 	if logger.IsDebugEnabled() {
 		logger.Debug("Fetched user",
@@ -240,6 +164,7 @@ func CheckIfUserExistsByEmail(requestId string, email string) (bool, *Models.Err
 	}
 
 	var count int64
+	// deleted users are counted as well, their email stays taken until the account is purged
 	result := db.Model(&entity.User{}).Where("email = ?", email).Count(&count)
 
 	if result.Error != nil {
@@ -302,79 +227,63 @@ func SaveUser(requestId string, user *entity.User) (*entity.User, *Models.ErrorR
 	return user, nil
 }
 
-func UpdateForgotPasswordToken(requestId string, identity string, token string) (bool, *Models.ErrorResponse) {
+// SaveUserWithIdentity saves the user registered using an OAuth provider along with its provider identity
+func SaveUserWithIdentity(
+	requestId string,
+	user *entity.User,
+	identity *entity.UserIdentity,
+) (*entity.User, *Models.ErrorResponse) {
 	if logger.IsInfoEnabled() {
-		logger.Info("Updating forgot password token",
+		logger.Info("Saving user with identity into DB",
 			zap.String(constants.RequestIdLogKey, requestId),
-			zap.String("identity", identity),
+			zap.String("user_id", user.Id),
+			zap.String("provider", identity.Provider),
 		)
 	}
 
-	db := MySQL.GetInstance(requestId, "UpdateForgotPasswordToken")
+	db := MySQL.GetInstance(requestId, "SaveUserWithIdentity")
 
 	if db == nil {
 		logErrorGettingDBInstance(requestId)
-		return false, utils.InternalServerErrorResponse()
+		return nil, utils.InternalServerErrorResponse()
 	}
 
-	var affectedRows int64
-	timestamp := time.Now().UnixMilli()
-
-	result := db.Model(&entity.User{}).Where("id = ? or email = ?", identity, identity).UpdateColumns(
-		map[string]interface{}{
-			"ForgotPasswordToken": token,
-			"UpdatedAt":           timestamp,
-		},
-	).Count(&affectedRows)
+	now := time.Now().UnixMilli()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	identity.UserId = user.Id
+	identity.CreatedAt = now
+	identity.LastLoginAt = &now
 
-	if result.Error != nil {
-		if logger.IsErrorEnabled() {
-			logger.Error("Error updating forgot password token",
-				zap.String(constants.RequestIdLogKey, requestId),
-				zap.Error(result.Error),
-			)
-		}
-		return false, utils.InternalServerErrorResponse()
-	}
-
-	if affectedRows == 1 {
-		if logger.IsInfoEnabled() {
-			logger.Info("Forgot password token updated",
-				zap.String(constants.RequestIdLogKey, requestId),
-			)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
 		}
-		return true, nil
-	}
-
-	return false, utils.InternalServerErrorResponse()
-}
 
-func GetForgotPasswordToken(requestId string, email string) (string, *Models.ErrorResponse) {
-	if logger.IsInfoEnabled() {
-		logger.Info("Fetching user forgot token info from DB",
-			zap.String(constants.RequestIdLogKey, requestId),
-		)
-	}
-
-	user, err := GetUserByEmail(requestId, email)
+		return tx.Create(identity).Error
+	})
 
 	if err != nil {
-		return "", err
-	}
-
-	if user.ForgotPasswordToken == "" {
 		if logger.IsErrorEnabled() {
-			logger.Error("Invalid forgot password token fetched from DB",
+			logger.Error("Error saving user with identity",
 				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
 			)
 		}
-		return "", utils.GetErrorResponse("Invalid Forgot Password Token", 400)
+		return nil, utils.InternalServerErrorResponse()
 	}
 
-	return user.ForgotPasswordToken, nil
+	return user, nil
 }
 
-func UpdatePassword(requestId string, identity string, newPassword string) (bool, *Models.ErrorResponse) {
+// UpdatePassword replaces the password of the user and sets the login type, which changes when a password is added to
+// an account that didn't have one
+func UpdatePassword(
+	requestId string,
+	identity string,
+	newPassword string,
+	loginType constants.UserEntityLoginType,
+) (bool, *Models.ErrorResponse) {
 	if logger.IsInfoEnabled() {
 		logger.Info("Updating user password into DB",
 			zap.String(constants.RequestIdLogKey, requestId),
@@ -393,7 +302,7 @@ func UpdatePassword(requestId string, identity string, newPassword string) (bool
 
 	result := db.Model(&entity.User{}).Where("id = ? or email = ?", identity, identity).UpdateColumns(map[string]interface{}{
 		"password":              newPassword,
-		"ForgotPasswordToken":   "", // Empty string for the token
+		"UserLoginType":         loginType,
 		"LastPasswordChangedAt": timestamp,
 		"UpdatedAt":             timestamp,
 	}).Count(&affectedRows)
@@ -469,3 +378,100 @@ func UpdateTimestamp(requestId string, identity string, timestampType TimestampT
 		return
 	}
 }
+
+// SetTokensRevokedAt records that every token issued to the user till now is revoked
+func SetTokensRevokedAt(requestId string, userId string) *Models.ErrorResponse {
+	db := MySQL.GetInstance(requestId, "SetTokensRevokedAt")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	result := db.Model(&entity.User{}).Where("id = ?", userId).UpdateColumn("TokensRevokedAt", time.Now().UnixMilli())
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error setting tokens revoked timestamp",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	if result.RowsAffected != 1 {
+		return utils.GetErrorResponse("User not found with id", 404)
+	}
+
+	return nil
+}
+
+// MarkEmailVerified marks the email of the user verified. Returns false if the email was already verified
+func MarkEmailVerified(requestId string, userId string) (bool, *Models.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Marking user email verified",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", userId),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "MarkEmailVerified")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	timestamp := time.Now().UnixMilli()
+
+	result := db.Model(&entity.User{}).Where("id = ? and email_verified = ?", userId, false).UpdateColumns(map[string]interface{}{
+		"EmailVerified":   true,
+		"EmailVerifiedAt": timestamp,
+		"UpdatedAt":       timestamp,
+	})
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error marking user email verified",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// UpdatePasswordHash replaces the password hash of the user with an equivalent one, e.g. after the hashing algorithm
+// changed. The hash is only replaced if the password wasn't changed in the meantime
+func UpdatePasswordHash(requestId string, userId string, currentHash string, newHash string) {
+	db := MySQL.GetInstance(requestId, "UpdatePasswordHash")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return
+	}
+
+	result := db.Model(&entity.User{}).Where("id = ? and password = ?", userId, currentHash).UpdateColumns(map[string]interface{}{
+		"password":  newHash,
+		"UpdatedAt": time.Now().UnixMilli(),
+	})
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error updating password hash",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return
+	}
+
+	if result.RowsAffected == 1 && logger.IsInfoEnabled() {
+		logger.Info("Password hash upgraded",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+}
@@ -0,0 +1,264 @@
+package auth_dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// DeletedUserName is the name of the users once their account is purged
+const DeletedUserName = "Deleted User"
+
+// SoftDeleteUser marks the user deleted and revokes every access token issued to it. Returns the deletion timestamp
+func SoftDeleteUser(requestId string, userId string) (int64, *Models.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Soft deleting user",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", userId),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "SoftDeleteUser")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return 0, utils.InternalServerErrorResponse()
+	}
+
+	timestamp := time.Now().UnixMilli()
+
+	result := db.Model(&entity.User{}).Where("id = ? and is_deleted = ?", userId, false).UpdateColumns(map[string]interface{}{
+		"IsDeleted":       true,
+		"DeletedAt":       timestamp,
+		"TokensRevokedAt": timestamp,
+		"UpdatedAt":       timestamp,
+	})
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error soft deleting user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return 0, utils.InternalServerErrorResponse()
+	}
+
+	if result.RowsAffected != 1 {
+		return 0, utils.GetErrorResponse("User not found with id", 404)
+	}
+
+	return timestamp, nil
+}
+
+// GetDeletedUserById returns the user deleted after deletedAfter and not purged yet
+func GetDeletedUserById(requestId string, userId string, deletedAfter int64) (*entity.User, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetDeletedUserById")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var user entity.User
+
+	result := db.First(&user, "id = ? and is_deleted = ? and purged_at is null and deleted_at > ?", userId, true, deletedAfter)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, utils.GetErrorResponse("User not found with id", 404)
+		}
+
+		if logger.IsErrorEnabled() {
+			logger.Error("Error querying deleted user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return &user, nil
+}
+
+// RestoreUser reverts the deletion of the user if it happened after deletedAfter, i.e. if the grace period isn't over.
+// Returns false if the user isn't deleted or was already purged
+func RestoreUser(requestId string, userId string, deletedAfter int64) (bool, *Models.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Restoring deleted user",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", userId),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "RestoreUser")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	result := db.Model(&entity.User{}).
+		Where("id = ? and is_deleted = ? and purged_at is null and deleted_at > ?", userId, true, deletedAfter).
+		UpdateColumns(map[string]interface{}{
+			"IsDeleted": false,
+			"DeletedAt": nil,
+			"UpdatedAt": time.Now().UnixMilli(),
+		})
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error restoring user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// GetUsersToPurge returns up to limit users deleted at or before deletedBefore which are not purged yet
+func GetUsersToPurge(requestId string, deletedBefore int64, limit int) ([]entity.User, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetUsersToPurge")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var users []entity.User
+
+	result := db.Where("is_deleted = ? and purged_at is null and deleted_at <= ?", true, deletedBefore).
+		Order("deleted_at").
+		Limit(limit).
+		Find(&users)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error querying users to purge",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return users, nil
+}
+
+// PurgeUser anonymises the row of the deleted user and deletes its credentials, identities, sessions, exports and login
+// throttle. The row is kept so that the audit logs, whose details and client info are cleared, still refer to an
+// existing user. beforeCommit is called with the purge timestamp once the user is purged in the transaction, which is
+// rolled back if it fails. Returns false if the user was restored or purged in the meantime, e.g. by another instance
+func PurgeUser(
+	requestId string,
+	userId string,
+	deletedBefore int64,
+	beforeCommit func(purgedAt int64) error,
+) (bool, *Models.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Purging deleted user",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", userId),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "PurgeUser")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	purged := false
+	timestamp := time.Now().UnixMilli()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.User{}).
+			Where("id = ? and is_deleted = ? and purged_at is null and deleted_at <= ?", userId, true, deletedBefore).
+			UpdateColumns(map[string]interface{}{
+				"Email":             nil,
+				"Password":          nil,
+				"Name":              DeletedUserName,
+				"Bio":               nil,
+				"ProfilePictureURL": nil,
+				"Phone":             nil,
+				"City":              nil,
+				"State":             nil,
+				"Country":           nil,
+				"Zipcode":           nil,
+				"BusinessDetails":   nil,
+				"EmailVerified":     false,
+				"EmailVerifiedAt":   nil,
+				"PurgedAt":          timestamp,
+				"UpdatedAt":         timestamp,
+			})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected != 1 {
+			return nil
+		}
+
+		for _, model := range []interface{}{
+			&entity.UserIdentity{},
+			&entity.Session{},
+			&entity.RefreshToken{},
+			&entity.UserMfa{},
+			&entity.LoginOtp{},
+			&entity.ActionToken{},
+			&entity.PasswordHistory{},
+			&entity.DataExport{},
+		} {
+			if err := tx.Where("user_id = ?", userId).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		// the audit trail is kept, but its details may hold the emails of the user and its client info identifies them
+		if err := tx.Model(&entity.AuditLog{}).Where("user_id = ?", userId).UpdateColumns(map[string]interface{}{
+			"Details":   nil,
+			"IpAddress": "",
+			"UserAgent": "",
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("throttle_key = ?", constants.AccountThrottleKeyPrefix+userId).Delete(&entity.LoginThrottle{}).Error; err != nil {
+			return err
+		}
+
+		if err := beforeCommit(timestamp); err != nil {
+			return err
+		}
+
+		purged = true
+
+		return nil
+	})
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error purging user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("userId", userId),
+				zap.Error(err),
+			)
+		}
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	return purged, nil
+}
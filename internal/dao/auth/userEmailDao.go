@@ -0,0 +1,68 @@
+package auth_dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
+)
+
+// mysql error raised when a unique index is violated
+const mysqlDuplicateEntryErrorNumber = 1062
+
+// ChangeUserEmail replaces the email of the user with the confirmed one and marks it verified. Returns 409 if the email
+// was taken by another user in the meantime
+func ChangeUserEmail(requestId string, userId string, newEmail string) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Changing user email into DB",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", userId),
+			zap.String("email", newEmail),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "ChangeUserEmail")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	timestamp := time.Now().UnixMilli()
+
+	result := db.Model(&entity.User{}).Where("id = ? and is_deleted = ?", userId, false).UpdateColumns(map[string]interface{}{
+		"Email":           newEmail,
+		"EmailVerified":   true,
+		"EmailVerifiedAt": timestamp,
+		"UpdatedAt":       timestamp,
+	})
+
+	if result.Error != nil {
+		// the unique index of the email catches a user registered with it since it was checked
+		var mysqlError *mysql.MySQLError
+		if errors.As(result.Error, &mysqlError) && mysqlError.Number == mysqlDuplicateEntryErrorNumber {
+			return utils.GetErrorResponse("Email already registered", 409)
+		}
+
+		if logger.IsErrorEnabled() {
+			logger.Error("Error changing user email",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	if result.RowsAffected != 1 {
+		return utils.GetErrorResponse("User not found with id", 404)
+	}
+
+	return nil
+}
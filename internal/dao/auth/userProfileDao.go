@@ -0,0 +1,117 @@
+package auth_dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// GetUserEntityById returns the stored user including the profile fields, which the user model doesn't carry. Deleted
+// users are not returned
+func GetUserEntityById(requestId string, userId string) (*entity.User, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetUserEntityById")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var user entity.User
+
+	result := db.First(&user, "id = ? and is_deleted = ?", userId, false)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, utils.GetErrorResponse("User not found with id", 404)
+		}
+
+		if logger.IsErrorEnabled() {
+			logger.Error("Error querying user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return &user, nil
+}
+
+// UpdateUserProfile updates the profile columns of the user only if the profile wasn't updated since expectedUpdatedAt,
+// and returns the updated user. Returns 409 if the profile was updated in the meantime
+func UpdateUserProfile(
+	requestId string,
+	userId string,
+	expectedUpdatedAt int64,
+	columns map[string]interface{},
+) (*entity.User, *Models.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Updating user profile into DB",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", userId),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "UpdateUserProfile")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	updatedAt := time.Now().UnixMilli()
+
+	// the previous value must never be reused, otherwise a concurrent update made on it would succeed as well
+	if updatedAt <= expectedUpdatedAt {
+		updatedAt = expectedUpdatedAt + 1
+	}
+
+	updates := make(map[string]interface{}, len(columns)+1)
+
+	for column, value := range columns {
+		updates[column] = value
+	}
+
+	updates["UpdatedAt"] = updatedAt
+
+	result := db.Model(&entity.User{}).
+		Where("id = ? and is_deleted = ? and updated_at = ?", userId, false, expectedUpdatedAt).
+		UpdateColumns(updates)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error updating user profile",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	if result.RowsAffected == 0 {
+		// either the user doesn't exist anymore or the profile was updated since it was read
+		if _, err := GetUserEntityById(requestId, userId); err != nil {
+			return nil, err
+		}
+
+		if logger.IsInfoEnabled() {
+			logger.Info("User profile was updated concurrently",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("userId", userId),
+				zap.Int64("expectedUpdatedAt", expectedUpdatedAt),
+			)
+		}
+
+		return nil, utils.GetErrorResponse("Your profile was updated in the meantime. Reload it and try again", 409)
+	}
+
+	return GetUserEntityById(requestId, userId)
+}
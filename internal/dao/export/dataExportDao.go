@@ -0,0 +1,173 @@
+package export_dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+)
+
+func logErrorGettingDBInstance(requestId string) {
+	if logger.IsErrorEnabled() {
+		logger.Error("Error getting DB instance",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+}
+
+func SaveDataExport(requestId string, dataExport *entity.DataExport) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Saving data export into DB",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("user_id", dataExport.UserId),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "SaveDataExport")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	dataExport.CreatedAt = time.Now().UnixMilli()
+
+	if err := db.Create(dataExport).Error; err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error saving data export",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
+
+// GetDataExport returns the export of the user, or nil if it doesn't exist
+func GetDataExport(requestId string, userId string, id string) (*entity.DataExport, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetDataExport")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var dataExport entity.DataExport
+
+	result := db.First(&dataExport, "id = ? and user_id = ?", id, userId)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		if logger.IsErrorEnabled() {
+			logger.Error("Error fetching data export",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return &dataExport, nil
+}
+
+// HasPendingDataExport reports whether an export of the user created after createdAfter is still being generated
+func HasPendingDataExport(requestId string, userId string, createdAfter int64) (bool, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "HasPendingDataExport")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	var count int64
+
+	result := db.Model(&entity.DataExport{}).
+		Where("user_id = ? and status = ? and created_at > ?", userId, constants.DataExportStatusPending, createdAfter).
+		Count(&count)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error checking pending data exports",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	return count > 0, nil
+}
+
+// CompleteDataExport stores the generated data of the pending export and marks it ready, or marks it failed if data is
+// nil
+func CompleteDataExport(requestId string, id string, data []byte) *Models.ErrorResponse {
+	db := MySQL.GetInstance(requestId, "CompleteDataExport")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	columns := map[string]interface{}{
+		"Status":      constants.DataExportStatusReady,
+		"Data":        data,
+		"CompletedAt": time.Now().UnixMilli(),
+	}
+
+	if data == nil {
+		columns["Status"] = constants.DataExportStatusFailed
+	}
+
+	result := db.Model(&entity.DataExport{}).
+		Where("id = ? and status = ?", id, constants.DataExportStatusPending).
+		UpdateColumns(columns)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error completing data export",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
+
+// DeleteExpiredDataExports removes the exports expired before now. Returns the number of removed exports
+func DeleteExpiredDataExports(requestId string) (int64, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "DeleteExpiredDataExports")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return 0, utils.InternalServerErrorResponse()
+	}
+
+	result := db.Where("expires_at < ?", time.Now().UnixMilli()).Delete(&entity.DataExport{})
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error deleting expired data exports",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return 0, utils.InternalServerErrorResponse()
+	}
+
+	return result.RowsAffected, nil
+}
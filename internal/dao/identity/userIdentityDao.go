@@ -0,0 +1,211 @@
+package identity_dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+)
+
+func logErrorGettingDBInstance(requestId string) {
+	if logger.IsErrorEnabled() {
+		logger.Error("Error getting DB instance",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+}
+
+// mysql error raised when a unique index is violated
+const mysqlDuplicateEntryErrorNumber = 1062
+
+func isDuplicateKeyError(err error) bool {
+	var mysqlError *mysql.MySQLError
+	return errors.As(err, &mysqlError) && mysqlError.Number == mysqlDuplicateEntryErrorNumber
+}
+
+// SaveUserIdentity links the provider identity to its user. Returns 409 if the identity is already linked
+func SaveUserIdentity(requestId string, identity *entity.UserIdentity) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Saving user identity into DB",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("user_id", identity.UserId),
+			zap.String("provider", identity.Provider),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "SaveUserIdentity")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	identity.CreatedAt = time.Now().UnixMilli()
+
+	if err := db.Create(identity).Error; err != nil {
+		if isDuplicateKeyError(err) {
+			return utils.GetErrorResponse("This "+identity.Provider+" account is already linked to an account", 409)
+		}
+
+		if logger.IsErrorEnabled() {
+			logger.Error("Error saving user identity",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
+
+// GetUserIdentity returns the identity of the provider user, or nil if it is not linked to any user
+func GetUserIdentity(requestId string, provider string, providerUserId string) (*entity.UserIdentity, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetUserIdentity")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var identity entity.UserIdentity
+
+	result := db.First(&identity, "provider = ? AND provider_user_id = ?", provider, providerUserId)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		if logger.IsErrorEnabled() {
+			logger.Error("Error fetching user identity",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return &identity, nil
+}
+
+// GetUserIdentities returns the identities linked to the user, oldest first
+func GetUserIdentities(requestId string, userId string) ([]entity.UserIdentity, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetUserIdentities")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var identities []entity.UserIdentity
+
+	result := db.Where("user_id = ?", userId).Order("created_at ASC").Find(&identities)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error fetching user identities",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return identities, nil
+}
+
+// UpdateIdentityLastLoginTime records the time the user last logged in using the identity
+func UpdateIdentityLastLoginTime(requestId string, id string) {
+	db := MySQL.GetInstance(requestId, "UpdateIdentityLastLoginTime")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return
+	}
+
+	result := db.Model(&entity.UserIdentity{}).Where("id = ?", id).UpdateColumn("last_login_at", time.Now().UnixMilli())
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error updating identity last login time",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+	}
+}
+
+// DeleteUserIdentity unlinks the identity from the user. When keepLastIdentity is set the last identity of the user
+// is kept, the identities being locked so that concurrent requests can't remove them all. Returns false if the
+// identity was not deleted
+func DeleteUserIdentity(
+	requestId string,
+	userId string,
+	id string,
+	keepLastIdentity bool,
+) (bool, *Models.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Deleting user identity",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("user_id", userId),
+			zap.String("identity_id", id),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "DeleteUserIdentity")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	deleted := false
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var identities []entity.UserIdentity
+
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userId).Find(&identities)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if keepLastIdentity && len(identities) <= 1 {
+			return nil
+		}
+
+		result = tx.Where("id = ? AND user_id = ?", id, userId).Delete(&entity.UserIdentity{})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		deleted = result.RowsAffected == 1
+
+		return nil
+	})
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error deleting user identity",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	return deleted, nil
+}
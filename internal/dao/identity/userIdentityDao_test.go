@@ -0,0 +1,112 @@
+package identity_dao
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+var (
+	selectIdentitiesForUpdateQuery = regexp.QuoteMeta("SELECT * FROM `user_identities` WHERE user_id = ? FOR UPDATE")
+	deleteIdentityQuery            = regexp.QuoteMeta("DELETE FROM `user_identities` WHERE id = ? AND user_id = ?")
+)
+
+func newMockDB(t *testing.T) sqlmock.Sqlmock {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("creating sql mock: %v", err)
+	}
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{
+		Logger: gormLogger.Default.LogMode(gormLogger.Silent),
+	})
+
+	if err != nil {
+		t.Fatalf("opening gorm on sql mock: %v", err)
+	}
+
+	MySQL.SetInstance(db)
+
+	t.Cleanup(func() {
+		MySQL.SetInstance(nil)
+		_ = sqlDB.Close()
+	})
+
+	return mock
+}
+
+func identityRows(ids ...string) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "user_id", "provider", "provider_user_id"})
+
+	for _, id := range ids {
+		rows.AddRow(id, "user-1", "google", "google-"+id)
+	}
+
+	return rows
+}
+
+func TestDeleteUserIdentityKeepsLastIdentity(t *testing.T) {
+	mock := newMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(selectIdentitiesForUpdateQuery).WithArgs("user-1").WillReturnRows(identityRows("identity-1"))
+	mock.ExpectCommit()
+
+	deleted, err := DeleteUserIdentity("test", "user-1", "identity-1", true)
+
+	if err != nil {
+		t.Fatalf("DeleteUserIdentity returned error: %v", err.Message)
+	}
+
+	if deleted {
+		t.Error("DeleteUserIdentity deleted the last identity of a user without another login method")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDeleteUserIdentityDeletesWhenAnotherLoginMethodRemains(t *testing.T) {
+	testCases := []struct {
+		name             string
+		identities       []string
+		keepLastIdentity bool
+	}{
+		{name: "another identity", identities: []string{"identity-1", "identity-2"}, keepLastIdentity: true},
+		{name: "login without identity", identities: []string{"identity-1"}, keepLastIdentity: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mock := newMockDB(t)
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectIdentitiesForUpdateQuery).WithArgs("user-1").WillReturnRows(identityRows(testCase.identities...))
+			mock.ExpectExec(deleteIdentityQuery).WithArgs("identity-1", "user-1").WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			deleted, err := DeleteUserIdentity("test", "user-1", "identity-1", testCase.keepLastIdentity)
+
+			if err != nil {
+				t.Fatalf("DeleteUserIdentity returned error: %v", err.Message)
+			}
+
+			if !deleted {
+				t.Error("DeleteUserIdentity did not delete the identity")
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
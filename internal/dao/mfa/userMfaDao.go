@@ -0,0 +1,179 @@
+package mfa_dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+)
+
+func logErrorGettingDBInstance(requestId string) {
+	if logger.IsErrorEnabled() {
+		logger.Error("Error getting DB instance",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+}
+
+// GetUserMfa returns the MFA settings of the user, or nil if the user never enrolled
+func GetUserMfa(requestId string, userId string) (*entity.UserMfa, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetUserMfa")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var userMfa entity.UserMfa
+
+	result := db.First(&userMfa, "user_id = ?", userId)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		if logger.IsErrorEnabled() {
+			logger.Error("Error fetching user MFA",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return &userMfa, nil
+}
+
+// SaveUserMfa inserts or replaces the MFA settings of the user
+func SaveUserMfa(requestId string, userMfa *entity.UserMfa) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Saving user MFA into DB",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("user_id", userMfa.UserId),
+			zap.Bool("enabled", userMfa.Enabled),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "SaveUserMfa")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	now := time.Now().UnixMilli()
+
+	if userMfa.CreatedAt == 0 {
+		userMfa.CreatedAt = now
+	}
+
+	userMfa.UpdatedAt = now
+
+	result := db.Save(userMfa)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error saving user MFA",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
+
+// UpdateLastUsedStep records the time step of the accepted TOTP code. The update only succeeds if the step is newer
+// than the stored one, so the same code can't be accepted twice
+func UpdateLastUsedStep(requestId string, userId string, step int64) (bool, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "UpdateLastUsedStep")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	result := db.Model(&entity.UserMfa{}).
+		Where("user_id = ? AND last_used_step < ?", userId, step).
+		UpdateColumns(map[string]interface{}{"last_used_step": step, "updated_at": time.Now().UnixMilli()})
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error updating last used TOTP step",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// ReplaceRecoveryCodes swaps the recovery codes of the user only if they still match the expected ones, so a
+// recovery code consumed by a concurrent request can't be used again
+func ReplaceRecoveryCodes(requestId string, userId string, expected string, replacement string) (bool, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "ReplaceRecoveryCodes")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	result := db.Model(&entity.UserMfa{}).
+		Where("user_id = ? AND recovery_codes = ?", userId, expected).
+		UpdateColumns(map[string]interface{}{"recovery_codes": replacement, "updated_at": time.Now().UnixMilli()})
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error updating recovery codes",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func DeleteUserMfa(requestId string, userId string) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Deleting user MFA",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("user_id", userId),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "DeleteUserMfa")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	result := db.Where("user_id = ?", userId).Delete(&entity.UserMfa{})
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error deleting user MFA",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
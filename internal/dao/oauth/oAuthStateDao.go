@@ -0,0 +1,137 @@
+package oauth_dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+)
+
+func logErrorGettingDBInstance(requestId string) {
+	if logger.IsErrorEnabled() {
+		logger.Error("Error getting DB instance",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+}
+
+// SaveOAuthState stores the state of an authorization request along with its PKCE code verifier
+func SaveOAuthState(requestId string, oAuthState *entity.OAuthState) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Saving OAuth state into DB",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("provider", string(oAuthState.Provider)),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "SaveOAuthState")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	oAuthState.CreatedAt = time.Now().UnixMilli()
+
+	if err := db.Create(oAuthState).Error; err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error saving OAuth state",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
+
+// GetOAuthState returns the state with the given id, or nil if there is none
+func GetOAuthState(requestId string, id string) (*entity.OAuthState, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetOAuthState")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var oAuthState entity.OAuthState
+
+	result := db.First(&oAuthState, "id = ?", id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		if logger.IsErrorEnabled() {
+			logger.Error("Error fetching OAuth state",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return &oAuthState, nil
+}
+
+// MarkOAuthStateUsed marks the state as used. Returns false if the state was already used by another request
+func MarkOAuthStateUsed(requestId string, id string) (bool, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "MarkOAuthStateUsed")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	result := db.Model(&entity.OAuthState{}).
+		Where("id = ? AND used_at IS NULL", id).
+		UpdateColumn("used_at", time.Now().UnixMilli())
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error marking OAuth state used",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteExpiredOAuthStates removes the states of the authorization requests expired before now, used or not. Returns
+// the number of removed states
+func DeleteExpiredOAuthStates(requestId string) (int64, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "DeleteExpiredOAuthStates")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return 0, utils.InternalServerErrorResponse()
+	}
+
+	result := db.Where("expires_at < ?", time.Now().UnixMilli()).Delete(&entity.OAuthState{})
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error deleting expired OAuth states",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return 0, utils.InternalServerErrorResponse()
+	}
+
+	return result.RowsAffected, nil
+}
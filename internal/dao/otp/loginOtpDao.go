@@ -0,0 +1,141 @@
+package otp_dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+)
+
+func logErrorGettingDBInstance(requestId string) {
+	if logger.IsErrorEnabled() {
+		logger.Error("Error getting DB instance",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+}
+
+// SaveLoginOtp stores the OTP of the user, replacing the previously issued one
+func SaveLoginOtp(requestId string, loginOtp *entity.LoginOtp) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Saving login OTP into DB",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("user_id", loginOtp.UserId),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "SaveLoginOtp")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	loginOtp.CreatedAt = time.Now().UnixMilli()
+
+	result := db.Save(loginOtp)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error saving login OTP",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
+
+// GetLoginOtp returns the pending OTP of the user, or nil if there is none
+func GetLoginOtp(requestId string, userId string) (*entity.LoginOtp, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetLoginOtp")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var loginOtp entity.LoginOtp
+
+	result := db.First(&loginOtp, "user_id = ?", userId)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		if logger.IsErrorEnabled() {
+			logger.Error("Error fetching login OTP",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return &loginOtp, nil
+}
+
+// IncrementLoginOtpAttempts reserves an attempt to guess the given OTP of the user before it is checked. Returns false
+// if the OTP has no attempt left or was consumed or replaced by another request. The check and the increment are a
+// single statement so that parallel guesses can't exceed maxAttempts
+func IncrementLoginOtpAttempts(requestId string, userId string, codeHash string, maxAttempts int) (bool, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "IncrementLoginOtpAttempts")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	result := db.Model(&entity.LoginOtp{}).
+		Where("user_id = ? AND code_hash = ? AND attempts < ?", userId, codeHash, maxAttempts).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error incrementing login OTP attempts",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteLoginOtp removes the OTP of the user if it is still the given one. Returns false if it was already consumed
+// or replaced by another request
+func DeleteLoginOtp(requestId string, userId string, codeHash string) (bool, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "DeleteLoginOtp")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	result := db.Where("user_id = ? AND code_hash = ?", userId, codeHash).Delete(&entity.LoginOtp{})
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error deleting login OTP",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,104 @@
+package password_dao
+
+import (
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+)
+
+func logErrorGettingDBInstance(requestId string) {
+	if logger.IsErrorEnabled() {
+		logger.Error("Error getting DB instance",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+}
+
+// GetPasswordHistory returns the most recent password hashes of the user, newest first
+func GetPasswordHistory(requestId string, userId string, limit int) ([]entity.PasswordHistory, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetPasswordHistory")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var passwordHistory []entity.PasswordHistory
+
+	result := db.Where("user_id = ?", userId).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&passwordHistory)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error fetching password history of user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return passwordHistory, nil
+}
+
+// SavePasswordHistory stores the password hash of the user and removes the entries beyond the most recent depth ones
+func SavePasswordHistory(requestId string, passwordHistory *entity.PasswordHistory, depth int) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Saving password history into DB",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("user_id", passwordHistory.UserId),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "SavePasswordHistory")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	passwordHistory.CreatedAt = time.Now().UnixMilli()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(passwordHistory).Error; err != nil {
+			return err
+		}
+
+		var retainedIds []string
+
+		err := tx.Model(&entity.PasswordHistory{}).
+			Where("user_id = ?", passwordHistory.UserId).
+			Order("created_at DESC").
+			Limit(depth).
+			Pluck("id", &retainedIds).Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ? AND id NOT IN ?", passwordHistory.UserId, retainedIds).
+			Delete(&entity.PasswordHistory{}).Error
+	})
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error saving password history",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package session_dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+)
+
+func logErrorGettingDBInstance(requestId string) {
+	if logger.IsErrorEnabled() {
+		logger.Error("Error getting DB instance",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+}
+
+func SaveSession(requestId string, session *entity.Session) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Saving session into DB",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("user_id", session.UserId),
+			zap.String("session_id", session.Id),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "SaveSession")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	session.CreatedAt = time.Now().UnixMilli()
+	session.LastSeenAt = session.CreatedAt
+
+	result := db.Create(session)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error saving session",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
+
+func GetSessionById(requestId string, sessionId string) (*entity.Session, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetSessionById")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var session entity.Session
+
+	result := db.First(&session, "id = ?", sessionId)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			if logger.IsInfoEnabled() {
+				logger.Info("No session found with id",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.String("session_id", sessionId),
+				)
+			}
+			return nil, utils.GetErrorResponse("Session not found", 404)
+		}
+
+		if logger.IsErrorEnabled() {
+			logger.Error("Error querying session",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return &session, nil
+}
+
+// GetActiveSessionsByUserId returns the sessions of the user which are not revoked, most recently used first
+func GetActiveSessionsByUserId(requestId string, userId string) ([]entity.Session, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetActiveSessionsByUserId")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var sessions []entity.Session
+
+	result := db.Where("user_id = ? AND revoked_at IS NULL", userId).Order("last_seen_at desc").Find(&sessions)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error fetching sessions of user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return sessions, nil
+}
+
+// GetSessionsByUserId returns every session of the user including the revoked ones, most recent first
+func GetSessionsByUserId(requestId string, userId string) ([]entity.Session, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetSessionsByUserId")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var sessions []entity.Session
+
+	result := db.Where("user_id = ?", userId).Order("created_at desc").Find(&sessions)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error fetching sessions of user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return sessions, nil
+}
+
+// CountSessionsByUserId returns the number of sessions of the user including the revoked ones
+func CountSessionsByUserId(requestId string, userId string) (int64, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "CountSessionsByUserId")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return 0, utils.InternalServerErrorResponse()
+	}
+
+	var count int64
+
+	result := db.Model(&entity.Session{}).Where("user_id = ?", userId).Count(&count)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error counting sessions of user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return 0, utils.InternalServerErrorResponse()
+	}
+
+	return count, nil
+}
+
+func UpdateSessionLastSeen(requestId string, sessionId string) {
+	db := MySQL.GetInstance(requestId, "UpdateSessionLastSeen")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return
+	}
+
+	result := db.Model(&entity.Session{}).Where("id = ?", sessionId).UpdateColumn("last_seen_at", time.Now().UnixMilli())
+
+	if result.Error != nil && logger.IsErrorEnabled() {
+		logger.Error("Error updating session last seen time",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.Error(result.Error),
+		)
+	}
+}
+
+func RevokeSession(requestId string, sessionId string) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Revoking session",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("session_id", sessionId),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "RevokeSession")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	result := db.Model(&entity.Session{}).
+		Where("id = ? AND revoked_at IS NULL", sessionId).
+		UpdateColumn("revoked_at", time.Now().UnixMilli())
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error revoking session",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
+
+// RevokeUserSessions revokes every session of the user except the provided one. An empty exceptSessionId revokes all
+func RevokeUserSessions(requestId string, userId string, exceptSessionId string) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Revoking sessions of user",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("user_id", userId),
+			zap.String("except_session_id", exceptSessionId),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "RevokeUserSessions")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	result := db.Model(&entity.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userId, exceptSessionId).
+		UpdateColumn("revoked_at", time.Now().UnixMilli())
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error revoking sessions of user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package throttle_dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+)
+
+func logErrorGettingDBInstance(requestId string) {
+	if logger.IsErrorEnabled() {
+		logger.Error("Error getting DB instance",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+}
+
+// GetLoginThrottle returns the throttle state stored for the key, or nil if the key has no failed attempts
+func GetLoginThrottle(requestId string, throttleKey string) (*entity.LoginThrottle, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "GetLoginThrottle")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var throttle entity.LoginThrottle
+
+	result := db.First(&throttle, "throttle_key = ?", throttleKey)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		if logger.IsErrorEnabled() {
+			logger.Error("Error fetching login throttle",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return &throttle, nil
+}
+
+// RecordFailedLoginAttempt atomically applies the update function on the throttle state of the key and stores it.
+// The update function receives the current state, which is zero valued for a new key
+func RecordFailedLoginAttempt(
+	requestId string,
+	throttleKey string,
+	update func(throttle *entity.LoginThrottle),
+) (*entity.LoginThrottle, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "RecordFailedLoginAttempt")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var throttle entity.LoginThrottle
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&throttle, "throttle_key = ?", throttleKey)
+
+		if result.Error != nil {
+			if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return result.Error
+			}
+			throttle = entity.LoginThrottle{ThrottleKey: throttleKey}
+		}
+
+		update(&throttle)
+		throttle.UpdatedAt = time.Now().UnixMilli()
+
+		return tx.Save(&throttle).Error
+	})
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error recording failed login attempt",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("throttle_key", throttleKey),
+				zap.Error(err),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return &throttle, nil
+}
+
+// DeleteLoginThrottle clears the failed attempts and the lock of the key
+func DeleteLoginThrottle(requestId string, throttleKey string) *Models.ErrorResponse {
+	db := MySQL.GetInstance(requestId, "DeleteLoginThrottle")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	result := db.Where("throttle_key = ?", throttleKey).Delete(&entity.LoginThrottle{})
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error deleting login throttle",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("throttle_key", throttleKey),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package token_dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+)
+
+func logErrorGettingDBInstance(requestId string) {
+	if logger.IsErrorEnabled() {
+		logger.Error("Error getting DB instance",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+}
+
+func SaveRefreshToken(requestId string, refreshToken *entity.RefreshToken) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Saving refresh token into DB",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("user_id", refreshToken.UserId),
+			zap.String("family_id", refreshToken.FamilyId),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "SaveRefreshToken")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	refreshToken.CreatedAt = time.Now().UnixMilli()
+
+	result := db.Create(refreshToken)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error saving refresh token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
+
+func GetRefreshTokenByHash(requestId string, tokenHash string) (*entity.RefreshToken, *Models.ErrorResponse) {
+	if logger.IsDebugEnabled() {
+		logger.Debug("Getting refresh token by hash",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "GetRefreshTokenByHash")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var refreshToken entity.RefreshToken
+
+	result := db.First(&refreshToken, "token_hash = ?", tokenHash)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			if logger.IsInfoEnabled() {
+				logger.Info("No refresh token found with hash",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			return nil, utils.GetErrorResponse("Invalid refresh token", 401)
+		}
+
+		if logger.IsErrorEnabled() {
+			logger.Error("Error querying refresh token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return &refreshToken, nil
+}
+
+// MarkRefreshTokenUsed marks the refresh token as used. It returns false if the token was already used or revoked
+// by a concurrent request
+func MarkRefreshTokenUsed(requestId string, id string) (bool, *Models.ErrorResponse) {
+	if logger.IsDebugEnabled() {
+		logger.Debug("Marking refresh token as used",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("refresh_token_id", id),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "MarkRefreshTokenUsed")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	result := db.Model(&entity.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		UpdateColumn("used_at", time.Now().UnixMilli())
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error marking refresh token as used",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func RevokeRefreshTokenFamily(requestId string, familyId string) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Revoking refresh token family",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("family_id", familyId),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "RevokeRefreshTokenFamily")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	result := db.Model(&entity.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyId).
+		UpdateColumn("revoked_at", time.Now().UnixMilli())
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error revoking refresh token family",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	if logger.IsInfoEnabled() {
+		logger.Info("Refresh token family revoked",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("family_id", familyId),
+			zap.Int64("revoked_tokens", result.RowsAffected),
+		)
+	}
+
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes every refresh token of the user except the ones of the provided family. An empty
+// exceptFamilyId revokes all
+func RevokeUserRefreshTokens(requestId string, userId string, exceptFamilyId string) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Revoking refresh tokens of user",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("user_id", userId),
+			zap.String("except_family_id", exceptFamilyId),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "RevokeUserRefreshTokens")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	result := db.Model(&entity.RefreshToken{}).
+		Where("user_id = ? AND family_id <> ? AND revoked_at IS NULL", userId, exceptFamilyId).
+		UpdateColumn("revoked_at", time.Now().UnixMilli())
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error revoking refresh tokens of user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package token_dao
+
+import (
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+)
+
+func SaveRevokedToken(requestId string, revokedToken *entity.RevokedToken) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Saving revoked token into DB",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("user_id", revokedToken.UserId),
+			zap.String("jti", revokedToken.Jti),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "SaveRevokedToken")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	revokedToken.CreatedAt = time.Now().UnixMilli()
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(revokedToken)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error saving revoked token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
+
+func IsTokenRevoked(requestId string, jti string) (bool, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "IsTokenRevoked")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	var count int64
+	result := db.Model(&entity.RevokedToken{}).Where("jti = ?", jti).Count(&count)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error checking revoked token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return false, utils.InternalServerErrorResponse()
+	}
+
+	return count > 0, nil
+}
+
+// DeleteExpiredRevokedTokens removes the denylist entries of tokens which are expired anyway
+func DeleteExpiredRevokedTokens(requestId string) (int64, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "DeleteExpiredRevokedTokens")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return 0, utils.InternalServerErrorResponse()
+	}
+
+	result := db.Where("expires_at < ?", time.Now().Unix()).Delete(&entity.RevokedToken{})
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error deleting expired revoked tokens",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return 0, utils.InternalServerErrorResponse()
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,195 @@
+package token_dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	MySQL "github.com/akgarg0472/urlshortener-auth-service/database"
+	Models "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+)
+
+func FetchSigningKeys(requestId string) ([]entity.SigningKey, *Models.ErrorResponse) {
+	db := MySQL.GetInstance(requestId, "FetchSigningKeys")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var signingKeys []entity.SigningKey
+
+	result := db.Order("created_at").Find(&signingKeys)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error fetching signing keys",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return signingKeys, nil
+}
+
+func SaveSigningKey(requestId string, signingKey *entity.SigningKey) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Saving signing key into DB",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("kid", signingKey.Kid),
+			zap.String("algorithm", signingKey.Algorithm),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "SaveSigningKey")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	signingKey.CreatedAt = time.Now().UnixMilli()
+	signingKey.UpdatedAt = time.Now().UnixMilli()
+
+	result := db.Create(signingKey)
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error saving signing key",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.ParseMySQLErrorAndReturnErrorResponse(result.Error)
+	}
+
+	return nil
+}
+
+// SetCurrentSigningKey marks the key as the current signing key and clears the flag from every other key
+func SetCurrentSigningKey(requestId string, kid string) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Promoting signing key",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("kid", kid),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "SetCurrentSigningKey")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	timestamp := time.Now().UnixMilli()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.SigningKey{}).Where("kid = ?", kid).UpdateColumns(map[string]interface{}{
+			"IsCurrent": true,
+			"UpdatedAt": timestamp,
+		})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Model(&entity.SigningKey{}).Where("kid <> ? AND is_current = ?", kid, true).UpdateColumns(map[string]interface{}{
+			"IsCurrent": false,
+			"UpdatedAt": timestamp,
+		}).Error
+	})
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.GetErrorResponse("Signing key not found", 404)
+		}
+
+		if logger.IsErrorEnabled() {
+			logger.Error("Error promoting signing key",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
+
+func UpdateSigningKeyRetireAt(requestId string, kid string, retireAt int64) *Models.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Scheduling signing key retirement",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("kid", kid),
+			zap.Int64("retire_at", retireAt),
+		)
+	}
+
+	db := MySQL.GetInstance(requestId, "UpdateSigningKeyRetireAt")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	result := db.Model(&entity.SigningKey{}).Where("kid = ?", kid).UpdateColumns(map[string]interface{}{
+		"RetireAt":  retireAt,
+		"UpdatedAt": time.Now().UnixMilli(),
+	})
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error updating signing key retirement",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	if result.RowsAffected == 0 {
+		return utils.GetErrorResponse("Signing key not found", 404)
+	}
+
+	return nil
+}
+
+// UpdateSigningKeyFingerprint stores the fingerprint of the HS256 secret of the key
+func UpdateSigningKeyFingerprint(requestId string, kid string, fingerprint string) *Models.ErrorResponse {
+	db := MySQL.GetInstance(requestId, "UpdateSigningKeyFingerprint")
+
+	if db == nil {
+		logErrorGettingDBInstance(requestId)
+		return utils.InternalServerErrorResponse()
+	}
+
+	result := db.Model(&entity.SigningKey{}).Where("kid = ?", kid).UpdateColumns(map[string]interface{}{
+		"SecretFingerprint": fingerprint,
+		"UpdatedAt":         time.Now().UnixMilli(),
+	})
+
+	if result.Error != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error updating signing key fingerprint",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(result.Error),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
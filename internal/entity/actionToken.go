@@ -0,0 +1,18 @@
+package entity
+
+import enums "github.com/akgarg0472/urlshortener-auth-service/constants"
+
+type ActionToken struct {
+	Id        string                `gorm:"primaryKey;size:64" json:"id"`
+	UserId    string                `gorm:"size:128;index:idx_action_tokens_user_action;not null" json:"user_id"`
+	Action    enums.ActionTokenType `gorm:"type:varchar(32);index:idx_action_tokens_user_action;not null" json:"action"`
+	TokenHash string                `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	Payload   *string               `gorm:"type:text" json:"payload,omitempty"`
+	ExpiresAt int64                 `gorm:"type:bigint;not null" json:"expires_at"`
+	UsedAt    *int64                `gorm:"type:bigint" json:"used_at,omitempty"`
+	CreatedAt int64                 `gorm:"type:bigint" json:"created_at"`
+}
+
+func (ActionToken) TableName() string {
+	return "action_tokens"
+}
@@ -0,0 +1,18 @@
+package entity
+
+import enums "github.com/akgarg0472/urlshortener-auth-service/constants"
+
+type AuditLog struct {
+	Id        string           `gorm:"primaryKey;size:64" json:"id"`
+	UserId    *string          `gorm:"size:128;index" json:"user_id,omitempty"`
+	Event     enums.AuditEvent `gorm:"type:varchar(64);index;not null" json:"event"`
+	RequestId string           `gorm:"size:64" json:"request_id"`
+	IpAddress string           `gorm:"size:64" json:"ip_address"`
+	UserAgent string           `gorm:"type:text" json:"user_agent"`
+	Details   *string          `gorm:"type:text" json:"details,omitempty"`
+	CreatedAt int64            `gorm:"type:bigint;index" json:"created_at"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,18 @@
+package entity
+
+import enums "github.com/akgarg0472/urlshortener-auth-service/constants"
+
+type DataExport struct {
+	Id          string                 `gorm:"primaryKey;size:64" json:"id"`
+	UserId      string                 `gorm:"size:128;index;not null" json:"user_id"`
+	Format      enums.DataExportFormat `gorm:"type:varchar(8);not null" json:"format"`
+	Status      enums.DataExportStatus `gorm:"type:varchar(16);not null" json:"status"`
+	Data        []byte                 `gorm:"type:longblob" json:"-"`
+	CreatedAt   int64                  `gorm:"type:bigint" json:"created_at"`
+	CompletedAt *int64                 `gorm:"type:bigint" json:"completed_at,omitempty"`
+	ExpiresAt   int64                  `gorm:"type:bigint;index;not null" json:"expires_at"`
+}
+
+func (DataExport) TableName() string {
+	return "data_exports"
+}
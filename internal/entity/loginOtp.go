@@ -0,0 +1,13 @@
+package entity
+
+type LoginOtp struct {
+	UserId    string `gorm:"primaryKey;size:128" json:"user_id"`
+	CodeHash  string `gorm:"size:255;not null" json:"-"`
+	ExpiresAt int64  `gorm:"type:bigint;not null" json:"expires_at"`
+	Attempts  int    `gorm:"not null;default:0" json:"attempts"`
+	CreatedAt int64  `gorm:"type:bigint" json:"created_at"`
+}
+
+func (LoginOtp) TableName() string {
+	return "login_otps"
+}
@@ -0,0 +1,13 @@
+package entity
+
+type LoginThrottle struct {
+	ThrottleKey    string `gorm:"primaryKey;size:255" json:"throttle_key"`
+	FailedAttempts int    `gorm:"not null;default:0" json:"failed_attempts"`
+	LastFailedAt   int64  `gorm:"type:bigint" json:"last_failed_at"`
+	LockedUntil    int64  `gorm:"type:bigint;default:0" json:"locked_until"`
+	UpdatedAt      int64  `gorm:"type:bigint" json:"updated_at"`
+}
+
+func (LoginThrottle) TableName() string {
+	return "login_throttles"
+}
@@ -1,13 +1,20 @@
 package entity
 
+import enums "github.com/akgarg0472/urlshortener-auth-service/constants"
+
 type OAuthProvider struct {
-	ID          uint8  `gorm:"primaryKey"`
-	Provider    string `gorm:"size:255;not null;unique"`
-	ClientID    string `gorm:"size:255;unique;not null"`
-	BaseUrl     string `gorm:"size:255;unique;not null"`
-	RedirectURI string `gorm:"type:text;not null"`
-	AccessType  string `gorm:"size:50"`
-	Scope       string `gorm:"type:text"`
+	ID            uint8                   `gorm:"primaryKey"`
+	Provider      string                  `gorm:"size:255;not null;unique"`
+	Type          enums.OAuthProviderType `gorm:"type:varchar(16);not null;default:oauth2"`
+	ClientID      string                  `gorm:"size:255;unique;not null"`
+	ClientSecret  *string                 `gorm:"type:text"`
+	BaseUrl       string                  `gorm:"size:255;unique;not null"`
+	RedirectURI   string                  `gorm:"type:text;not null"`
+	AccessType    string                  `gorm:"size:50"`
+	Scope         string                  `gorm:"type:text"`
+	Issuer        *string                 `gorm:"size:255"`
+	DiscoveryUrl  *string                 `gorm:"size:255"`
+	ClaimMappings *string                 `gorm:"type:text"`
 }
 
 func (OAuthProvider) TableName() string {
@@ -0,0 +1,20 @@
+package entity
+
+import enums "github.com/akgarg0472/urlshortener-auth-service/constants"
+
+type OAuthState struct {
+	Id           string                  `gorm:"primaryKey;size:64" json:"id"`
+	Provider     enums.OAuthProvider     `gorm:"type:varchar(32);not null" json:"provider"`
+	Purpose      enums.OAuthStatePurpose `gorm:"type:varchar(32);not null;default:login" json:"purpose"`
+	CodeVerifier string                  `gorm:"size:128;not null" json:"-"`
+	Nonce        *string                 `gorm:"size:64" json:"-"`
+	LinkUserId   *string                 `gorm:"size:128" json:"link_user_id,omitempty"`
+	BindingHash  *string                 `gorm:"size:64" json:"-"`
+	ExpiresAt    int64                   `gorm:"type:bigint;index;not null" json:"expires_at"`
+	UsedAt       *int64                  `gorm:"type:bigint" json:"used_at,omitempty"`
+	CreatedAt    int64                   `gorm:"type:bigint" json:"created_at"`
+}
+
+func (OAuthState) TableName() string {
+	return "oauth_states"
+}
@@ -0,0 +1,12 @@
+package entity
+
+type PasswordHistory struct {
+	Id           string `gorm:"primaryKey;size:64" json:"id"`
+	UserId       string `gorm:"size:128;index;not null" json:"user_id"`
+	PasswordHash string `gorm:"size:255;not null" json:"-"`
+	CreatedAt    int64  `gorm:"type:bigint;index" json:"created_at"`
+}
+
+func (PasswordHistory) TableName() string {
+	return "password_history"
+}
@@ -0,0 +1,16 @@
+package entity
+
+type RefreshToken struct {
+	Id        string `gorm:"primaryKey;size:64" json:"id"`
+	UserId    string `gorm:"size:128;index;not null" json:"user_id"`
+	FamilyId  string `gorm:"size:64;index;not null" json:"family_id"`
+	TokenHash string `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	ExpiresAt int64  `gorm:"type:bigint;not null" json:"expires_at"`
+	UsedAt    *int64 `gorm:"type:bigint" json:"used_at,omitempty"`
+	RevokedAt *int64 `gorm:"type:bigint" json:"revoked_at,omitempty"`
+	CreatedAt int64  `gorm:"type:bigint" json:"created_at"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
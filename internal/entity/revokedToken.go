@@ -0,0 +1,12 @@
+package entity
+
+type RevokedToken struct {
+	Jti       string `gorm:"primaryKey;size:64" json:"jti"`
+	UserId    string `gorm:"size:128;index;not null" json:"user_id"`
+	ExpiresAt int64  `gorm:"type:bigint;index;not null" json:"expires_at"`
+	CreatedAt int64  `gorm:"type:bigint" json:"created_at"`
+}
+
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
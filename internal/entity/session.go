@@ -0,0 +1,18 @@
+package entity
+
+import enums "github.com/akgarg0472/urlshortener-auth-service/constants"
+
+type Session struct {
+	Id          string                   `gorm:"primaryKey;size:64" json:"id"`
+	UserId      string                   `gorm:"size:128;index;not null" json:"user_id"`
+	UserAgent   string                   `gorm:"type:text" json:"user_agent"`
+	IpAddress   string                   `gorm:"size:64" json:"ip_address"`
+	LoginMethod enums.SessionLoginMethod `gorm:"type:varchar(32)" json:"login_method"`
+	CreatedAt   int64                    `gorm:"type:bigint" json:"created_at"`
+	LastSeenAt  int64                    `gorm:"type:bigint" json:"last_seen_at"`
+	RevokedAt   *int64                   `gorm:"type:bigint" json:"revoked_at,omitempty"`
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
@@ -0,0 +1,16 @@
+package entity
+
+type SigningKey struct {
+	Kid               string `gorm:"primaryKey;size:64" json:"kid"`
+	Algorithm         string `gorm:"size:16;not null" json:"algorithm"`
+	PrivateKeyPath    string `gorm:"type:text" json:"private_key_path"`
+	SecretFingerprint string `gorm:"size:64" json:"-"`
+	IsCurrent         bool   `gorm:"default:0" json:"is_current"`
+	RetireAt          *int64 `gorm:"type:bigint" json:"retire_at,omitempty"`
+	CreatedAt         int64  `gorm:"type:bigint" json:"created_at"`
+	UpdatedAt         int64  `gorm:"type:bigint" json:"updated_at"`
+}
+
+func (SigningKey) TableName() string {
+	return "jwt_signing_keys"
+}
@@ -12,17 +12,19 @@ type User struct {
 	ProfilePictureURL     *string                   `gorm:"type:text" json:"profile_picture_url,omitempty"` // text
 	Phone                 *string                   `gorm:"size:20" json:"phone,omitempty"`                 // varchar(20)
 	UserLoginType         enums.UserEntityLoginType `gorm:"type:varchar(50)" json:"login_type"`
-	OAuthId               *string                   `gorm:"column:oauth_id;uniqueIndex;size:255" json:"oauth_id,omitempty"`
-	OAuthProvider         *string                   `gorm:"column:oauth_provider;size:16" json:"oauth_provider,omitempty"`
+	EmailVerified         bool                      `gorm:"default:0" json:"email_verified"`                       // tinyint(1)
+	EmailVerifiedAt       *int64                    `gorm:"type:bigint" json:"email_verified_at,omitempty"`        // bigint
 	City                  *string                   `gorm:"size:50" json:"city,omitempty"`                         // varchar(50)
 	State                 *string                   `gorm:"size:50" json:"state,omitempty"`                        // varchar(50)
 	Country               *string                   `gorm:"size:50" json:"country,omitempty"`                      // varchar(50)
 	Zipcode               *string                   `gorm:"size:16" json:"zipcode,omitempty"`                      // varchar(16)
 	BusinessDetails       *string                   `gorm:"type:text" json:"business_details,omitempty"`           // text
-	ForgotPasswordToken   *string                   `gorm:"size:255" json:"forgot_password_token,omitempty"`       // varchar(255)
 	LastPasswordChangedAt *int64                    `gorm:"type:bigint" json:"last_password_changed_at,omitempty"` // bigint
 	LastLoginAt           *int64                    `gorm:"type:bigint" json:"last_login_at,omitempty"`            // bigint
+	TokensRevokedAt       *int64                    `gorm:"type:bigint" json:"tokens_revoked_at,omitempty"`        // bigint
 	IsDeleted             bool                      `gorm:"default:0" json:"is_deleted"`                           // tinyint(1)
+	DeletedAt             *int64                    `gorm:"type:bigint;index" json:"deleted_at,omitempty"`         // bigint
+	PurgedAt              *int64                    `gorm:"type:bigint" json:"purged_at,omitempty"`                // bigint
 	CreatedAt             int64                     `gorm:"type:bigint;" json:"created_at"`                        // timestamp
 	UpdatedAt             int64                     `gorm:"type:bigint;" json:"updated_at"`                        // timestamp
 }
@@ -0,0 +1,15 @@
+package entity
+
+type UserIdentity struct {
+	Id             string  `gorm:"primaryKey;size:64" json:"id"`
+	UserId         string  `gorm:"size:128;index;not null" json:"user_id"`
+	Provider       string  `gorm:"size:64;uniqueIndex:idx_user_identities_provider_user;not null" json:"provider"`
+	ProviderUserId string  `gorm:"size:255;uniqueIndex:idx_user_identities_provider_user;not null" json:"provider_user_id"`
+	Email          *string `gorm:"size:255" json:"email,omitempty"`
+	CreatedAt      int64   `gorm:"type:bigint" json:"created_at"`
+	LastLoginAt    *int64  `gorm:"type:bigint" json:"last_login_at,omitempty"`
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
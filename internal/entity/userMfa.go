@@ -0,0 +1,16 @@
+package entity
+
+type UserMfa struct {
+	UserId          string `gorm:"primaryKey;size:128" json:"user_id"`
+	EncryptedSecret string `gorm:"type:text;not null" json:"-"`
+	Enabled         bool   `gorm:"default:0" json:"enabled"`
+	RecoveryCodes   string `gorm:"type:text" json:"-"`
+	LastUsedStep    int64  `gorm:"type:bigint;default:0" json:"-"`
+	EnabledAt       *int64 `gorm:"type:bigint" json:"enabled_at,omitempty"`
+	CreatedAt       int64  `gorm:"type:bigint" json:"created_at"`
+	UpdatedAt       int64  `gorm:"type:bigint" json:"updated_at"`
+}
+
+func (UserMfa) TableName() string {
+	return "user_mfa"
+}
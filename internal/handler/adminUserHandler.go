@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	throttleService "github.com/akgarg0472/urlshortener-auth-service/internal/service/throttle"
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+)
+
+// UnlockAccountHandler Handler function to unlock an account locked after too many failed logins
+func UnlockAccountHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	userId := chi.URLParam(httpRequest, "userId")
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Unlock account request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", userId),
+		)
+	}
+
+	unlockAccountResponse, unlockAccountError := throttleService.UnlockAccount(requestId, userId)
+
+	sendResponseToClient(responseWriter, requestId, unlockAccountResponse, unlockAccountError, 200)
+}
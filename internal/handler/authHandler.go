@@ -26,23 +26,145 @@ func LoginHandler(responseWriter http.ResponseWriter, httpRequest *http.Request)
 		)
 	}
 
-	loginResponse, loginError := auth_service.LoginWithEmailPassword(requestId, loginRequest)
+	loginResponse, loginError := auth_service.LoginWithEmailPassword(requestId, loginRequest, utils.ExtractClientInfo(httpRequest))
 
-	cookie := &http.Cookie{
-		Name:     "auth_token",
-		Value:    loginResponse.AccessToken,
-		HttpOnly: true,
-		Secure:   true,
-		Path:     "/",
-		Expires:  time.Now().Add(24 * time.Hour),
-		SameSite: http.SameSiteNoneMode,
+	if loginError == nil && !loginResponse.MfaRequired {
+		setAuthTokenCookie(responseWriter, loginResponse.AccessToken)
 	}
 
-	http.SetCookie(responseWriter, cookie)
+	sendResponseToClient(responseWriter, requestId, loginResponse, loginError, 200)
+}
+
+// MfaLoginHandler Handler Function to complete the login using the MFA challenge token and a TOTP or recovery code
+func MfaLoginHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	mfaLoginRequest := context.Value(utils.RequestContextKeys.MfaLoginRequestKey).(model.MfaLoginRequest)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("MFA login request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	loginResponse, loginError := auth_service.LoginWithMfa(requestId, mfaLoginRequest, utils.ExtractClientInfo(httpRequest))
+
+	if loginError == nil {
+		setAuthTokenCookie(responseWriter, loginResponse.AccessToken)
+	}
+
+	sendResponseToClient(responseWriter, requestId, loginResponse, loginError, 200)
+}
+
+// LoginOtpRequestHandler Handler Function to send a one-time login code to the user
+func LoginOtpRequestHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	loginOtpRequest := context.Value(utils.RequestContextKeys.LoginOtpRequestKey).(model.LoginOtpRequest)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Login OTP request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.Any(constants.RequestLogKey, loginOtpRequest),
+		)
+	}
+
+	loginOtpResponse, loginOtpError := auth_service.RequestLoginOtp(requestId, loginOtpRequest)
+
+	sendResponseToClient(responseWriter, requestId, loginOtpResponse, loginOtpError, 200)
+}
+
+// LoginOtpVerifyHandler Handler Function to log in the user using the one-time login code
+func LoginOtpVerifyHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	loginOtpVerifyRequest := context.Value(utils.RequestContextKeys.LoginOtpVerifyRequestKey).(model.LoginOtpVerifyRequest)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Login OTP verify request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("email", loginOtpVerifyRequest.Email),
+		)
+	}
+
+	loginResponse, loginError := auth_service.LoginWithOtp(requestId, loginOtpVerifyRequest, utils.ExtractClientInfo(httpRequest))
+
+	if loginError == nil && !loginResponse.MfaRequired {
+		setAuthTokenCookie(responseWriter, loginResponse.AccessToken)
+	}
 
 	sendResponseToClient(responseWriter, requestId, loginResponse, loginError, 200)
 }
 
+// MagicLinkRequestHandler Handler Function to send a magic login link to the user
+func MagicLinkRequestHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	magicLinkRequest := context.Value(utils.RequestContextKeys.MagicLinkRequestKey).(model.MagicLinkRequest)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Magic link request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.Any(constants.RequestLogKey, magicLinkRequest),
+		)
+	}
+
+	magicLinkResponse, magicLinkError := auth_service.RequestMagicLink(requestId, magicLinkRequest)
+
+	sendResponseToClient(responseWriter, requestId, magicLinkResponse, magicLinkError, 200)
+}
+
+// VerifyMagicLinkHandler Handler Function to log in the user using the magic link and redirect to the frontend
+func VerifyMagicLinkHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Magic link verification request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	loginResponse, redirectUrl, err := auth_service.VerifyMagicLink(requestId, httpRequest.URL.Query(), utils.ExtractClientInfo(httpRequest))
+
+	if err != nil {
+		sendResponseToClient(responseWriter, requestId, nil, err, 200)
+		return
+	}
+
+	if !loginResponse.MfaRequired {
+		setAuthTokenCookie(responseWriter, loginResponse.AccessToken)
+	}
+
+	http.Redirect(responseWriter, httpRequest, redirectUrl, http.StatusSeeOther)
+}
+
+// RefreshTokenHandler Handler Function to handle refresh token rotation request
+func RefreshTokenHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	refreshTokenRequest := context.Value(utils.RequestContextKeys.RefreshTokenRequestKey).(model.RefreshTokenRequest)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Refresh token request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.Any(constants.RequestLogKey, refreshTokenRequest),
+		)
+	}
+
+	refreshTokenResponse, refreshTokenError := auth_service.RefreshAccessToken(requestId, refreshTokenRequest)
+
+	if refreshTokenError == nil {
+		setAuthTokenCookie(responseWriter, refreshTokenResponse.AccessToken)
+	}
+
+	sendResponseToClient(responseWriter, requestId, refreshTokenResponse, refreshTokenError, 200)
+}
+
 // SignupHandler Handler Function to handle signup request
 func SignupHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
 	context := httpRequest.Context()
@@ -62,6 +184,45 @@ func SignupHandler(responseWriter http.ResponseWriter, httpRequest *http.Request
 	sendResponseToClient(responseWriter, requestId, signupResponse, signupError, 201)
 }
 
+// VerifyEmailHandler Handler Function to verify the email of the user using the emailed link and redirect to the frontend
+func VerifyEmailHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Email verification request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	redirectUrl, err := auth_service.VerifyEmail(requestId, httpRequest.URL.Query())
+
+	if err != nil {
+		sendResponseToClient(responseWriter, requestId, nil, err, 200)
+		return
+	}
+
+	http.Redirect(responseWriter, httpRequest, redirectUrl, http.StatusSeeOther)
+}
+
+// ResendVerificationEmailHandler Handler Function to send a new email verification link to the user
+func ResendVerificationEmailHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	resendRequest := context.Value(utils.RequestContextKeys.ResendVerificationKey).(model.ResendVerificationEmailRequest)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Resend verification email request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.Any(constants.RequestLogKey, resendRequest),
+		)
+	}
+
+	resendResponse, resendError := auth_service.ResendVerificationEmail(requestId, resendRequest)
+
+	sendResponseToClient(responseWriter, requestId, resendResponse, resendError, 200)
+}
+
 // LogoutHandler Handler Function to handle logout request
 func LogoutHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
 	context := httpRequest.Context()
@@ -76,6 +237,10 @@ func LogoutHandler(responseWriter http.ResponseWriter, httpRequest *http.Request
 		)
 	}
 
+	if logoutRequest.AuthToken == "" {
+		logoutRequest.AuthToken = utils.ExtractAuthToken(httpRequest)
+	}
+
 	logoutResponse, logoutError := auth_service.Logout(requestId, logoutRequest)
 
 	cookie := &http.Cookie{
@@ -126,7 +291,7 @@ func ForgotPasswordHandler(responseWriter http.ResponseWriter, httpRequest *http
 		)
 	}
 
-	forgotPasswordResponse, forgotPasswordError := auth_service.GenerateAndSendForgotPasswordToken(requestId, forgotPasswordRequest)
+	forgotPasswordResponse, forgotPasswordError := auth_service.GenerateAndSendForgotPasswordToken(requestId, forgotPasswordRequest, utils.ExtractClientInfo(httpRequest))
 
 	sendResponseToClient(responseWriter, requestId, forgotPasswordResponse, forgotPasswordError, 200)
 }
@@ -144,7 +309,7 @@ func VerifyResetPasswordHandler(responseWriter http.ResponseWriter, httpRequest
 		)
 	}
 
-	redirectUrl, err := auth_service.VerifyResetPasswordToken(requestId, queryParams)
+	redirectUrl, err := auth_service.VerifyResetPasswordToken(requestId, queryParams, utils.ExtractClientInfo(httpRequest))
 
 	if err != nil {
 		sendResponseToClient(responseWriter, requestId, nil, err, 200)
@@ -168,11 +333,48 @@ func ResetPasswordHandler(responseWriter http.ResponseWriter, httpRequest *http.
 		)
 	}
 
-	resetPasswordResponse, resetPasswordError := auth_service.ResetPassword(requestId, resetPasswordRequest)
+	resetPasswordResponse, resetPasswordError := auth_service.ResetPassword(requestId, resetPasswordRequest, utils.ExtractClientInfo(httpRequest))
 
 	sendResponseToClient(responseWriter, requestId, resetPasswordResponse, resetPasswordError, 200)
 }
 
+// ChangePasswordHandler Handler function to handle password change request of the authenticated user
+func ChangePasswordHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := context.Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+	changePasswordRequest := context.Value(utils.RequestContextKeys.ChangePasswordRequestKey).(model.ChangePasswordRequest)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Password change request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.Any(constants.RequestLogKey, changePasswordRequest),
+		)
+	}
+
+	changePasswordResponse, changePasswordError := auth_service.ChangePassword(requestId, authClaims, changePasswordRequest, utils.ExtractClientInfo(httpRequest))
+
+	sendResponseToClient(responseWriter, requestId, changePasswordResponse, changePasswordError, 200)
+}
+
+// PasswordSetupHandler Handler function to handle the request of an OAuth user to add a password to the account
+func PasswordSetupHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := httpRequest.Context().Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Password setup request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+		)
+	}
+
+	passwordSetupResponse, passwordSetupError := auth_service.RequestPasswordSetup(requestId, authClaims, utils.ExtractClientInfo(httpRequest))
+
+	sendResponseToClient(responseWriter, requestId, passwordSetupResponse, passwordSetupError, 200)
+}
+
 // VerifyAdminHandler Handler function to handle verify admin request
 func VerifyAdminHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
 	context := httpRequest.Context()
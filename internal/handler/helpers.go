@@ -2,6 +2,9 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/akgarg0472/urlshortener-auth-service/constants"
 	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
@@ -10,9 +13,104 @@ import (
 	"go.uber.org/zap"
 )
 
+// Function to set the auth token cookie on the response
+func setAuthTokenCookie(responseWriter http.ResponseWriter, authToken string) {
+	cookie := &http.Cookie{
+		Name:     "auth_token",
+		Value:    authToken,
+		HttpOnly: true,
+		Secure:   true,
+		Path:     "/",
+		Expires:  time.Now().Add(24 * time.Hour),
+		SameSite: http.SameSiteNoneMode,
+	}
+
+	http.SetCookie(responseWriter, cookie)
+}
+
+// name of the cookie binding an OAuth login to the browser which started it
+const oAuthBindingCookieName = "oauth_binding"
+
+// path of the OAuth endpoints, the only ones the binding cookie is sent to
+const oAuthBindingCookiePath = "/api/v1/auth/oauth"
+
+// Function to set the cookie binding the OAuth login to the browser. It expires along with the authorization request
+func setOAuthBindingCookie(responseWriter http.ResponseWriter, binding string, expiresAt int64) {
+	cookie := &http.Cookie{
+		Name:     oAuthBindingCookieName,
+		Value:    binding,
+		HttpOnly: true,
+		Secure:   true,
+		Path:     oAuthBindingCookiePath,
+		Expires:  time.UnixMilli(expiresAt),
+		SameSite: getOAuthBindingCookieSameSite(),
+	}
+
+	http.SetCookie(responseWriter, cookie)
+}
+
+// Function to remove the OAuth binding cookie once the callback is processed
+func clearOAuthBindingCookie(responseWriter http.ResponseWriter) {
+	cookie := &http.Cookie{
+		Name:     oAuthBindingCookieName,
+		Value:    "",
+		HttpOnly: true,
+		Secure:   true,
+		Path:     oAuthBindingCookiePath,
+		MaxAge:   -1,
+		SameSite: getOAuthBindingCookieSameSite(),
+	}
+
+	http.SetCookie(responseWriter, cookie)
+}
+
+// Function to return the OAuth binding cookie from the request, or an empty string if there is none
+func getOAuthBindingCookie(httpRequest *http.Request) string {
+	cookie, err := httpRequest.Cookie(oAuthBindingCookieName)
+
+	if err != nil {
+		return ""
+	}
+
+	return cookie.Value
+}
+
+// Function to return the SameSite mode of the OAuth binding cookie. It defaults to None like the auth token cookie, as
+// the frontend usually calls the API from another site. Lax or Strict should be used when both share a site
+func getOAuthBindingCookieSameSite() http.SameSite {
+	switch strings.ToLower(utils.GetEnvVariable("OAUTH_BINDING_COOKIE_SAME_SITE", "none")) {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "strict":
+		return http.SameSiteStrictMode
+	default:
+		return http.SameSiteNoneMode
+	}
+}
+
+// Function to send the file back to client as an attachment
+func sendFileToClient(responseWriter http.ResponseWriter, requestId string, file *model.DataExportFile) {
+	responseWriter.Header().Set("Content-Type", file.ContentType)
+	responseWriter.Header().Set("Content-Disposition", "attachment; filename=\""+file.FileName+"\"")
+	responseWriter.Header().Set("Content-Length", strconv.Itoa(len(file.Data)))
+	responseWriter.Header().Set("Cache-Control", "no-store")
+	responseWriter.WriteHeader(http.StatusOK)
+
+	if _, err := responseWriter.Write(file.Data); err != nil && logger.IsErrorEnabled() {
+		logger.Error("Error writing file to client",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.Error(err),
+		)
+	}
+}
+
 // Function to send response back to client
 func sendResponseToClient(responseWriter http.ResponseWriter, requestId string, response interface{}, err *model.ErrorResponse, statusCode int) {
 	if err != nil {
+		if err.RetryAfter > 0 {
+			responseWriter.Header().Set("Retry-After", strconv.FormatInt(err.RetryAfter, 10))
+		}
+
 		errorJson, _ := utils.ConvertToJsonString(err)
 		sendResponseToClientWithStatusAndMessage(responseWriter, int(err.ErrorCode), errorJson)
 		return
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	tokenService "github.com/akgarg0472/urlshortener-auth-service/internal/service/token"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+)
+
+// IntrospectionHandler Handler function to handle RFC 7662 token introspection request
+func IntrospectionHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	clientId := context.Value(utils.RequestContextKeys.IntrospectionClientIdKey).(string)
+	token := httpRequest.PostForm.Get("token")
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Token introspection request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("clientId", clientId),
+			zap.String("tokenTypeHint", httpRequest.PostForm.Get("token_type_hint")),
+		)
+	}
+
+	if token == "" {
+		sendResponseToClient(responseWriter, requestId, nil, utils.BadRequestErrorResponse("invalid_request"), 400)
+		return
+	}
+
+	introspectionResponse := tokenService.GetInstance().IntrospectToken(requestId, token, clientId)
+
+	responseWriter.Header().Set("Cache-Control", "no-store")
+	sendResponseToClient(responseWriter, requestId, introspectionResponse, nil, 200)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	mfaService "github.com/akgarg0472/urlshortener-auth-service/internal/service/mfa"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+)
+
+// EnrollTotpHandler Handler function to start the TOTP enrollment of the authenticated user
+func EnrollTotpHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := httpRequest.Context().Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("TOTP enrollment request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("authClaims", authClaims.String()),
+		)
+	}
+
+	enrollmentResponse, enrollmentError := mfaService.EnrollTotp(requestId, authClaims)
+
+	sendResponseToClient(responseWriter, requestId, enrollmentResponse, enrollmentError, 200)
+}
+
+// ConfirmTotpHandler Handler function to enable TOTP after verifying the first code
+func ConfirmTotpHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := context.Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+	totpCodeRequest := context.Value(utils.RequestContextKeys.TotpCodeRequestKey).(model.TotpCodeRequest)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("TOTP confirmation request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("authClaims", authClaims.String()),
+		)
+	}
+
+	confirmResponse, confirmError := mfaService.ConfirmTotp(requestId, authClaims, totpCodeRequest)
+
+	sendResponseToClient(responseWriter, requestId, confirmResponse, confirmError, 200)
+}
+
+// DisableTotpHandler Handler function to disable TOTP of the authenticated user
+func DisableTotpHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := context.Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+	totpCodeRequest := context.Value(utils.RequestContextKeys.TotpCodeRequestKey).(model.TotpCodeRequest)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("TOTP disable request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("authClaims", authClaims.String()),
+		)
+	}
+
+	disableResponse, disableError := mfaService.DisableTotp(requestId, authClaims, totpCodeRequest)
+
+	sendResponseToClient(responseWriter, requestId, disableResponse, disableError, 200)
+}
@@ -2,7 +2,8 @@ package handler
 
 import (
 	"net/http"
-	"time"
+
+	"github.com/go-chi/chi"
 
 	"github.com/akgarg0472/urlshortener-auth-service/constants"
 	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
@@ -25,6 +26,26 @@ func GetOAuthProvidersHandler(responseWriter http.ResponseWriter, httpRequest *h
 	sendResponseToClient(responseWriter, "", response, nil, 200)
 }
 
+func OAuthAuthorizeHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	provider := chi.URLParam(httpRequest, "provider")
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("OAuth Authorize request received on handler",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("provider", provider),
+		)
+	}
+
+	authorizeResponse, authorizeError := oauth_service.BuildAuthorizationUrl(requestId, provider)
+
+	if authorizeError == nil {
+		setOAuthBindingCookie(responseWriter, authorizeResponse.BrowserBinding, authorizeResponse.ExpiresAt)
+	}
+
+	sendResponseToClient(responseWriter, requestId, authorizeResponse, authorizeError, 200)
+}
+
 func OAuthCallbackHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
 	context := httpRequest.Context()
 
@@ -38,21 +59,101 @@ func OAuthCallbackHandler(responseWriter http.ResponseWriter, httpRequest *http.
 		)
 	}
 
-	oAuthCallbackResponse, oAuthCallbackError := oauth_service.ProcessCallbackRequest(requestId, oAuthCallbackRequest)
+	oAuthCallbackResponse, oAuthCallbackError := oauth_service.ProcessCallbackRequest(
+		requestId,
+		oAuthCallbackRequest,
+		getOAuthBindingCookie(httpRequest),
+		utils.ExtractClientInfo(httpRequest),
+	)
 
-	if oAuthCallbackResponse.Success {
-		cookie := &http.Cookie{
-			Name:     "auth_token",
-			Value:    oAuthCallbackResponse.AuthToken,
-			HttpOnly: true,
-			Secure:   true,
-			Path:     "/",
-			Expires:  time.Now().Add(24 * time.Hour),
-			SameSite: http.SameSiteNoneMode,
-		}
+	if oAuthCallbackError == nil {
+		clearOAuthBindingCookie(responseWriter)
 
-		http.SetCookie(responseWriter, cookie)
+		// users with MFA enabled get their tokens from the MFA login instead
+		if oAuthCallbackResponse.Success && oAuthCallbackResponse.AuthToken != "" {
+			setAuthTokenCookie(responseWriter, oAuthCallbackResponse.AuthToken)
+		}
 	}
 
 	sendResponseToClient(responseWriter, requestId, oAuthCallbackResponse, oAuthCallbackError, 200)
 }
+
+// StartOAuthIdentityLinkHandler Handler function to start linking an identity of the provider to the authenticated user
+func StartOAuthIdentityLinkHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := context.Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+	linkRequest := context.Value(utils.RequestContextKeys.LinkOAuthIdentityRequestKey).(model.LinkOAuthIdentityRequest)
+	provider := chi.URLParam(httpRequest, "provider")
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Start OAuth identity link request received on handler",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("authClaims", authClaims.String()),
+			zap.String("provider", provider),
+		)
+	}
+
+	linkResponse, linkError := oauth_service.StartIdentityLink(requestId, authClaims, provider, linkRequest, utils.ExtractClientInfo(httpRequest))
+
+	sendResponseToClient(responseWriter, requestId, linkResponse, linkError, 200)
+}
+
+// LinkOAuthIdentityHandler Handler function to complete linking the identity returned by the provider to the
+// authenticated user
+func LinkOAuthIdentityHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := context.Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+	callbackRequest := context.Value(utils.RequestContextKeys.OAuthCallbackRequestKey).(model.OAuthCallbackRequest)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Link OAuth identity request received on handler",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("authClaims", authClaims.String()),
+			zap.Any("callbackRequest", callbackRequest),
+		)
+	}
+
+	linkResponse, linkError := oauth_service.LinkIdentity(requestId, authClaims, callbackRequest, utils.ExtractClientInfo(httpRequest))
+
+	sendResponseToClient(responseWriter, requestId, linkResponse, linkError, 200)
+}
+
+// ListOAuthIdentitiesHandler Handler function to list the provider identities linked to the authenticated user
+func ListOAuthIdentitiesHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := httpRequest.Context().Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("List OAuth identities request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("authClaims", authClaims.String()),
+		)
+	}
+
+	identitiesResponse, identitiesError := oauth_service.GetIdentities(requestId, authClaims)
+
+	sendResponseToClient(responseWriter, requestId, identitiesResponse, identitiesError, 200)
+}
+
+// UnlinkOAuthIdentityHandler Handler function to unlink a provider identity from the authenticated user
+func UnlinkOAuthIdentityHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := httpRequest.Context().Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+	identityId := chi.URLParam(httpRequest, "id")
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Unlink OAuth identity request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("authClaims", authClaims.String()),
+			zap.String("identityId", identityId),
+		)
+	}
+
+	unlinkResponse, unlinkError := oauth_service.UnlinkIdentity(requestId, authClaims, identityId, utils.ExtractClientInfo(httpRequest))
+
+	sendResponseToClient(responseWriter, requestId, unlinkResponse, unlinkError, 200)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	sessionService "github.com/akgarg0472/urlshortener-auth-service/internal/service/session"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+)
+
+// ListSessionsHandler Handler function to list the active sessions of the authenticated user
+func ListSessionsHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := httpRequest.Context().Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("List sessions request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("authClaims", authClaims.String()),
+		)
+	}
+
+	sessionsResponse, sessionsError := sessionService.ListSessions(requestId, authClaims)
+
+	sendResponseToClient(responseWriter, requestId, sessionsResponse, sessionsError, 200)
+}
+
+// RevokeSessionHandler Handler function to revoke one of the sessions of the authenticated user
+func RevokeSessionHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := httpRequest.Context().Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+	sessionId := chi.URLParam(httpRequest, "id")
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Revoke session request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("authClaims", authClaims.String()),
+			zap.String("sessionId", sessionId),
+		)
+	}
+
+	revokeSessionResponse, revokeSessionError := sessionService.RevokeSession(requestId, authClaims, sessionId)
+
+	sendResponseToClient(responseWriter, requestId, revokeSessionResponse, revokeSessionError, 200)
+}
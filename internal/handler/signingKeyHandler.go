@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	tokenService "github.com/akgarg0472/urlshortener-auth-service/internal/service/token"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+)
+
+// ListSigningKeysHandler Handler function to list the keys of the signing key ring
+func ListSigningKeysHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+	sendResponseToClient(responseWriter, requestId, tokenService.GetInstance().ListSigningKeys(requestId), nil, 200)
+}
+
+// AddSigningKeyHandler Handler function to add a new key to the signing key ring
+func AddSigningKeyHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	addSigningKeyRequest := context.Value(utils.RequestContextKeys.AddSigningKeyRequestKey).(model.AddSigningKeyRequest)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Add signing key request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.Any(constants.RequestLogKey, addSigningKeyRequest),
+		)
+	}
+
+	addSigningKeyResponse, addSigningKeyError := tokenService.GetInstance().AddSigningKey(requestId, addSigningKeyRequest)
+
+	sendResponseToClient(responseWriter, requestId, addSigningKeyResponse, addSigningKeyError, 201)
+}
+
+// PromoteSigningKeyHandler Handler function to make a key the current signing key
+func PromoteSigningKeyHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	kid := chi.URLParam(httpRequest, "kid")
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Promote signing key request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("kid", kid),
+		)
+	}
+
+	promoteSigningKeyResponse, promoteSigningKeyError := tokenService.GetInstance().PromoteSigningKey(requestId, kid)
+
+	sendResponseToClient(responseWriter, requestId, promoteSigningKeyResponse, promoteSigningKeyError, 200)
+}
+
+// RetireSigningKeyHandler Handler function to schedule the retirement of a signing key
+func RetireSigningKeyHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	kid := chi.URLParam(httpRequest, "kid")
+	retireSigningKeyRequest := context.Value(utils.RequestContextKeys.RetireSigningKeyRequestKey).(model.RetireSigningKeyRequest)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Retire signing key request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("kid", kid),
+			zap.Any(constants.RequestLogKey, retireSigningKeyRequest),
+		)
+	}
+
+	retireSigningKeyResponse, retireSigningKeyError := tokenService.GetInstance().RetireSigningKey(requestId, kid, retireSigningKeyRequest)
+
+	sendResponseToClient(responseWriter, requestId, retireSigningKeyResponse, retireSigningKeyError, 200)
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	oauthService "github.com/akgarg0472/urlshortener-auth-service/internal/service/auth/oauth"
+	userService "github.com/akgarg0472/urlshortener-auth-service/internal/service/user"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+)
+
+// GetProfileHandler Handler function to return the profile of the authenticated user
+func GetProfileHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := httpRequest.Context().Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Get profile request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("authClaims", authClaims.String()),
+		)
+	}
+
+	profileResponse, profileError := userService.GetProfile(requestId, authClaims)
+
+	sendResponseToClient(responseWriter, requestId, profileResponse, profileError, 200)
+}
+
+// UpdateProfileHandler Handler function to update the profile of the authenticated user
+func UpdateProfileHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := context.Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+	updateProfileRequest := context.Value(utils.RequestContextKeys.UpdateProfileRequestKey).(model.UpdateProfileRequest)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Update profile request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.Any(constants.RequestLogKey, updateProfileRequest),
+		)
+	}
+
+	profileResponse, profileError := userService.UpdateProfile(requestId, authClaims, updateProfileRequest, utils.ExtractClientInfo(httpRequest))
+
+	sendResponseToClient(responseWriter, requestId, profileResponse, profileError, 200)
+}
+
+// StartReauthenticationHandler Handler function to start the authorization request the authenticated user completes to
+// confirm a sensitive operation using one of its linked providers
+func StartReauthenticationHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := httpRequest.Context().Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+	provider := chi.URLParam(httpRequest, "provider")
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Start reauthentication request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("authClaims", authClaims.String()),
+			zap.String("provider", provider),
+		)
+	}
+
+	authorizeResponse, authorizeError := oauthService.StartReauthentication(requestId, authClaims, provider)
+
+	sendResponseToClient(responseWriter, requestId, authorizeResponse, authorizeError, 200)
+}
+
+// DeleteAccountHandler Handler function to delete the account of the authenticated user
+func DeleteAccountHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := context.Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+	deleteAccountRequest := context.Value(utils.RequestContextKeys.DeleteAccountRequestKey).(model.DeleteAccountRequest)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Delete account request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.Any(constants.RequestLogKey, deleteAccountRequest),
+		)
+	}
+
+	deleteResponse, deleteError := userService.DeleteAccount(requestId, authClaims, deleteAccountRequest, utils.ExtractClientInfo(httpRequest))
+
+	sendResponseToClient(responseWriter, requestId, deleteResponse, deleteError, 200)
+}
+
+// RestoreAccountHandler Handler function to restore the deleted account using the emailed link and redirect to the
+// frontend
+func RestoreAccountHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Restore account request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	redirectUrl, err := userService.RestoreAccount(requestId, httpRequest.URL.Query(), utils.ExtractClientInfo(httpRequest))
+
+	if err != nil {
+		sendResponseToClient(responseWriter, requestId, nil, err, 200)
+		return
+	}
+
+	http.Redirect(responseWriter, httpRequest, redirectUrl, http.StatusSeeOther)
+}
+
+// ExportDataHandler Handler function to export the data stored about the authenticated user. The export is sent as a file
+// when it is generated directly, otherwise 202 is returned and the download link is emailed
+func ExportDataHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := httpRequest.Context().Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+	format := httpRequest.URL.Query().Get("format")
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Data export request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("authClaims", authClaims.String()),
+			zap.String("format", format),
+		)
+	}
+
+	exportFile, exportResponse, exportError := userService.ExportData(requestId, authClaims, format, utils.ExtractClientInfo(httpRequest))
+
+	if exportFile != nil {
+		sendFileToClient(responseWriter, requestId, exportFile)
+		return
+	}
+
+	sendResponseToClient(responseWriter, requestId, exportResponse, exportError, 202)
+}
+
+// DownloadDataExportHandler Handler function to download the export generated in the background using the emailed link
+func DownloadDataExportHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Data export download request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	exportFile, err := userService.DownloadDataExport(requestId, httpRequest.URL.Query(), utils.ExtractClientInfo(httpRequest))
+
+	if err != nil {
+		sendResponseToClient(responseWriter, requestId, nil, err, 200)
+		return
+	}
+
+	sendFileToClient(responseWriter, requestId, exportFile)
+}
+
+// RequestEmailChangeHandler Handler function to start the change of the email of the authenticated user
+func RequestEmailChangeHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	context := httpRequest.Context()
+
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+	authClaims := context.Value(utils.RequestContextKeys.AuthClaimsKey).(model.AuthClaims)
+	changeEmailRequest := context.Value(utils.RequestContextKeys.ChangeEmailRequestKey).(model.ChangeEmailRequest)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Change email request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.Any(constants.RequestLogKey, changeEmailRequest),
+		)
+	}
+
+	changeResponse, changeError := userService.RequestEmailChange(requestId, authClaims, changeEmailRequest, utils.ExtractClientInfo(httpRequest))
+
+	sendResponseToClient(responseWriter, requestId, changeResponse, changeError, 200)
+}
+
+// ConfirmEmailChangeHandler Handler function to apply the email change using the link sent to the new address and
+// redirect to the frontend
+func ConfirmEmailChangeHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Confirm email change request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	redirectUrl, err := userService.ConfirmEmailChange(requestId, httpRequest.URL.Query(), utils.ExtractClientInfo(httpRequest))
+
+	if err != nil {
+		sendResponseToClient(responseWriter, requestId, nil, err, 200)
+		return
+	}
+
+	http.Redirect(responseWriter, httpRequest, redirectUrl, http.StatusSeeOther)
+}
+
+// CancelEmailChangeHandler Handler function to cancel the pending email change using the link sent to the current
+// address and redirect to the frontend
+func CancelEmailChangeHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Cancel email change request received",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	redirectUrl, err := userService.CancelEmailChange(requestId, httpRequest.URL.Query(), utils.ExtractClientInfo(httpRequest))
+
+	if err != nil {
+		sendResponseToClient(responseWriter, requestId, nil, err, 200)
+		return
+	}
+
+	http.Redirect(responseWriter, httpRequest, redirectUrl, http.StatusSeeOther)
+}
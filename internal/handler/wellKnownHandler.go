@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"net/http"
+
+	tokenService "github.com/akgarg0472/urlshortener-auth-service/internal/service/token"
+)
+
+// JWKSHandler Handler function to publish the public keys used to verify the JWT tokens
+func JWKSHandler(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+	responseWriter.Header().Set("Cache-Control", "public, max-age=300")
+	sendResponseToClient(responseWriter, "", tokenService.GetInstance().GetJWKS(), nil, 200)
+}
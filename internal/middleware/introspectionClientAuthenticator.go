@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"mime"
+	"net/http"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	tokenService "github.com/akgarg0472/urlshortener-auth-service/internal/service/token"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+)
+
+// AuthenticateIntrospectionClient validates the form encoded introspection request and authenticates the calling
+// client using HTTP Basic authentication or the client_id and client_secret form parameters
+func AuthenticateIntrospectionClient(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		mediaType, _, _ := mime.ParseMediaType(httpRequest.Header.Get("Content-Type"))
+
+		if mediaType != "application/x-www-form-urlencoded" {
+			if logger.IsErrorEnabled() {
+				logger.Error("Content-Type not supported for introspection request",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.String("Content-Type", mediaType),
+				)
+			}
+			writeErrorResponse(responseWriter, http.StatusBadRequest, utils.GetErrorResponseByte("invalid_request", 400))
+			return
+		}
+
+		if err := httpRequest.ParseForm(); err != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error parsing introspection request form",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(err),
+				)
+			}
+			writeErrorResponse(responseWriter, http.StatusBadRequest, utils.GetErrorResponseByte("invalid_request", 400))
+			return
+		}
+
+		clientId, clientSecret, found := httpRequest.BasicAuth()
+
+		if !found {
+			clientId = httpRequest.PostForm.Get("client_id")
+			clientSecret = httpRequest.PostForm.Get("client_secret")
+		}
+
+		if clientId == "" || !tokenService.GetInstance().AuthenticateIntrospectionClient(clientId, clientSecret) {
+			if logger.IsErrorEnabled() {
+				logger.Error("Introspection client authentication failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.String("clientId", clientId),
+				)
+			}
+			responseWriter.Header().Set("WWW-Authenticate", `Basic realm="introspection"`)
+			writeErrorResponse(responseWriter, http.StatusUnauthorized, utils.GetErrorResponseByte("invalid_client", 401))
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.IntrospectionClientIdKey, clientId)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
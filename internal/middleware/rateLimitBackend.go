@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimitResult is the outcome of taking a token from the bucket of a key
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+// RateLimitBackend stores the token buckets of the rate limited keys. The in-memory backend is enough for a single
+// instance, deployments running multiple instances should share the buckets using the Redis backend
+type RateLimitBackend interface {
+	Take(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error)
+}
+
+// RedisEvaluator is the subset of a Redis client required by the Redis backend. Clients of most Redis libraries can
+// be adapted to it with a one line wrapper around their EVAL command
+type RedisEvaluator interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+type tokenBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+type inMemoryRateLimitBackend struct {
+	mutex   sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// NewInMemoryRateLimitBackend creates a backend keeping the token buckets in the memory of the instance
+func NewInMemoryRateLimitBackend(cleanupInterval time.Duration) RateLimitBackend {
+	backend := &inMemoryRateLimitBackend{
+		buckets: make(map[string]*tokenBucket),
+	}
+
+	go func() {
+		for {
+			time.Sleep(cleanupInterval)
+			backend.removeIdleBuckets(cleanupInterval)
+		}
+	}()
+
+	return backend
+}
+
+func (backend *inMemoryRateLimitBackend) Take(_ context.Context, key string, limit int, window time.Duration) (RateLimitResult, error) {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
+	now := time.Now()
+	refillRate := float64(limit) / window.Seconds()
+
+	bucket, exists := backend.buckets[key]
+
+	if !exists {
+		bucket = &tokenBucket{tokens: float64(limit), updatedAt: now}
+		backend.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(float64(limit), bucket.tokens+now.Sub(bucket.updatedAt).Seconds()*refillRate)
+	bucket.updatedAt = now
+
+	return takeToken(&bucket.tokens, limit, refillRate), nil
+}
+
+// removeIdleBuckets drops the buckets which were not used for the given duration and are therefore full again
+func (backend *inMemoryRateLimitBackend) removeIdleBuckets(idleDuration time.Duration) {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
+	for key, bucket := range backend.buckets {
+		if time.Since(bucket.updatedAt) > idleDuration {
+			delete(backend.buckets, key)
+		}
+	}
+}
+
+// redisTokenBucketScript refills and takes a token from the bucket stored as a hash in a single atomic step. The window
+// and timestamps are in milliseconds. Returns the remaining tokens multiplied by 1000 and whether the token was taken
+const redisTokenBucketScript = `
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local rate = limit / window
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated_at')
+local tokens = tonumber(bucket[1]) or limit
+local updatedAt = tonumber(bucket[2]) or now
+tokens = math.min(limit, tokens + math.max(0, now - updatedAt) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated_at', now)
+redis.call('PEXPIRE', KEYS[1], window)
+return {math.floor(tokens * 1000), allowed}
+`
+
+// redisClientEvaluator adapts the go-redis client to RedisEvaluator
+type redisClientEvaluator struct {
+	client *redis.Client
+}
+
+func (evaluator redisClientEvaluator) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return evaluator.client.Eval(ctx, script, keys, args...).Result()
+}
+
+type redisRateLimitBackend struct {
+	client    RedisEvaluator
+	keyPrefix string
+}
+
+// NewRedisRateLimitBackend creates a backend sharing the token buckets of all instances through Redis
+func NewRedisRateLimitBackend(client RedisEvaluator, keyPrefix string) RateLimitBackend {
+	return &redisRateLimitBackend{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}
+}
+
+func (backend *redisRateLimitBackend) Take(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error) {
+	// the script divides by the window, which must not be truncated to 0
+	windowMillis := max(window.Milliseconds(), 1)
+
+	reply, err := backend.client.Eval(ctx, redisTokenBucketScript, []string{backend.keyPrefix + key}, limit, windowMillis, time.Now().UnixMilli())
+
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	values, ok := reply.([]interface{})
+
+	if !ok || len(values) != 2 {
+		return RateLimitResult{}, fmt.Errorf("unexpected reply from rate limit script: %v", reply)
+	}
+
+	scaledTokens, _ := values[0].(int64)
+	allowed, _ := values[1].(int64)
+
+	tokens := float64(scaledTokens) / 1000
+	refillRate := float64(limit) / (time.Duration(windowMillis) * time.Millisecond).Seconds()
+
+	result := bucketResult(tokens, limit, refillRate)
+	result.Allowed = allowed == 1
+
+	if !result.Allowed {
+		result.RetryAfter = time.Duration((1 - tokens) / refillRate * float64(time.Second))
+	}
+
+	return result, nil
+}
+
+// takeToken takes a token from the refilled bucket if one is available
+func takeToken(tokens *float64, limit int, refillRate float64) RateLimitResult {
+	if *tokens >= 1 {
+		*tokens--
+		result := bucketResult(*tokens, limit, refillRate)
+		result.Allowed = true
+		return result
+	}
+
+	result := bucketResult(*tokens, limit, refillRate)
+	result.RetryAfter = time.Duration((1 - *tokens) / refillRate * float64(time.Second))
+
+	return result
+}
+
+func bucketResult(tokens float64, limit int, refillRate float64) RateLimitResult {
+	return RateLimitResult{
+		Limit:      limit,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: time.Duration((float64(limit) - tokens) / refillRate * float64(time.Second)),
+	}
+}
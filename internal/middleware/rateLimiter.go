@@ -0,0 +1,227 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	AuthModels "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var (
+	rateLimitBackend     RateLimitBackend
+	rateLimitBackendOnce sync.Once
+)
+
+// RateLimitKeyFunc returns the key whose requests are counted together. Requests with an empty key are not limited
+type RateLimitKeyFunc func(httpRequest *http.Request) string
+
+// RateLimitPolicy allows Limit requests per Window for every key returned by KeyFunc
+type RateLimitPolicy struct {
+	Name    string
+	Limit   int
+	Window  time.Duration
+	KeyFunc RateLimitKeyFunc
+}
+
+// NewRateLimitPolicy creates a rate limit policy. The limit and window can be overridden with the
+// RATE_LIMIT_<NAME> environment variable in the "<limit>/<window seconds>" format
+func NewRateLimitPolicy(name string, limit int, window time.Duration, keyFunc RateLimitKeyFunc) RateLimitPolicy {
+	policy := RateLimitPolicy{
+		Name:    name,
+		Limit:   limit,
+		Window:  window,
+		KeyFunc: keyFunc,
+	}
+
+	envVariable := "RATE_LIMIT_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	limitValue, windowValue, found := strings.Cut(utils.GetEnvVariable(envVariable, ""), "/")
+
+	if !found {
+		return policy
+	}
+
+	parsedLimit, limitErr := strconv.Atoi(strings.TrimSpace(limitValue))
+	parsedWindow, windowErr := strconv.Atoi(strings.TrimSpace(windowValue))
+
+	if limitErr != nil || windowErr != nil || parsedLimit <= 0 || parsedWindow <= 0 {
+		if logger.IsWarnEnabled() {
+			logger.Warn("Invalid rate limit configuration, using defaults",
+				zap.String("variable", envVariable),
+			)
+		}
+		return policy
+	}
+
+	policy.Limit = parsedLimit
+	policy.Window = time.Duration(parsedWindow) * time.Second
+
+	return policy
+}
+
+// SetRateLimitBackend replaces the in-memory backend, e.g. with NewRedisRateLimitBackend when the service runs with
+// multiple instances. Must be called before the server starts accepting requests
+func SetRateLimitBackend(backend RateLimitBackend) {
+	rateLimitBackendOnce.Do(func() {})
+	rateLimitBackend = backend
+}
+
+// InitRateLimitBackend sets the backend configured by RATE_LIMIT_BACKEND. The redis backend shares the buckets of
+// every instance through the server at RATE_LIMIT_REDIS_URL, the default memory backend keeps them per instance
+func InitRateLimitBackend() {
+	backendType := strings.ToLower(utils.GetEnvVariable("RATE_LIMIT_BACKEND", "memory"))
+
+	switch backendType {
+	case "memory":
+		return
+	case "redis":
+		redisUrl := utils.GetEnvVariable("RATE_LIMIT_REDIS_URL", "")
+
+		options, err := redis.ParseURL(redisUrl)
+
+		if err != nil {
+			panic(fmt.Sprintf("Invalid RATE_LIMIT_REDIS_URL: %v", err))
+		}
+
+		client := redis.NewClient(options)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := client.Ping(ctx).Err(); err != nil {
+			panic(fmt.Sprintf("Error connecting to the rate limit Redis server: %v", err))
+		}
+
+		SetRateLimitBackend(NewRedisRateLimitBackend(redisClientEvaluator{client: client}, utils.GetEnvVariable("RATE_LIMIT_REDIS_KEY_PREFIX", "rate_limit:")))
+
+		if logger.IsInfoEnabled() {
+			logger.Info("Rate limit backend initialized",
+				zap.String("backend", backendType),
+				zap.String("redisAddr", options.Addr),
+			)
+		}
+	default:
+		panic(fmt.Sprintf("Unsupported RATE_LIMIT_BACKEND: %s", backendType))
+	}
+}
+
+func getRateLimitBackend() RateLimitBackend {
+	rateLimitBackendOnce.Do(func() {
+		rateLimitBackend = NewInMemoryRateLimitBackend(utils.GetEnvDurationSeconds("RATE_LIMIT_CLEANUP_INTERVAL_SECONDS", 10*time.Minute))
+	})
+
+	return rateLimitBackend
+}
+
+// RateLimit rejects the requests exceeding the policy with 429 status code. Every response carries the RateLimit-*
+// headers describing the state of the bucket, rejected ones carry Retry-After as well
+func RateLimit(policy RateLimitPolicy) func(http.Handler) http.Handler {
+	enabled, err := strconv.ParseBool(utils.GetEnvVariable("RATE_LIMIT_ENABLED", "true"))
+
+	return func(next http.Handler) http.Handler {
+		if err == nil && !enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+			requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+			key := policy.KeyFunc(httpRequest)
+
+			if key == "" {
+				next.ServeHTTP(responseWriter, httpRequest)
+				return
+			}
+
+			result, takeErr := getRateLimitBackend().Take(httpRequest.Context(), policy.Name+":"+key, policy.Limit, policy.Window)
+
+			// requests are let through when the backend is unavailable rather than failing the auth flows
+			if takeErr != nil {
+				if logger.IsErrorEnabled() {
+					logger.Error("Error applying rate limit",
+						zap.String(constants.RequestIdLogKey, requestId),
+						zap.String("policy", policy.Name),
+						zap.Error(takeErr),
+					)
+				}
+				next.ServeHTTP(responseWriter, httpRequest)
+				return
+			}
+
+			setRateLimitHeaders(responseWriter, policy, result)
+
+			if !result.Allowed {
+				if logger.IsInfoEnabled() {
+					logger.Info("Rate limit exceeded",
+						zap.String(constants.RequestIdLogKey, requestId),
+						zap.String("policy", policy.Name),
+						zap.String("key", key),
+					)
+				}
+				responseWriter.Header().Set("Retry-After", strconv.FormatInt(ceilSeconds(result.RetryAfter), 10))
+				writeErrorResponse(responseWriter, http.StatusTooManyRequests, utils.GetErrorResponseByte("Too many requests. Please try again later", 429))
+				return
+			}
+
+			next.ServeHTTP(responseWriter, httpRequest)
+		})
+	}
+}
+
+// RateLimitKeyByIp counts the requests per source IP address
+func RateLimitKeyByIp(httpRequest *http.Request) string {
+	return utils.ExtractClientInfo(httpRequest).IpAddress
+}
+
+// RateLimitKeyByEmail counts the requests per email of the request body. The body validator of the route must run
+// before the rate limiter so that the decoded request is available in the context
+func RateLimitKeyByEmail(httpRequest *http.Request) string {
+	ctx := httpRequest.Context()
+
+	var email string
+
+	if loginRequest, ok := ctx.Value(utils.RequestContextKeys.LoginRequestKey).(AuthModels.LoginRequest); ok {
+		email = loginRequest.Email
+	} else if signupRequest, ok := ctx.Value(utils.RequestContextKeys.SignupRequestKey).(AuthModels.SignupRequest); ok {
+		email = signupRequest.Email
+	} else if loginOtpRequest, ok := ctx.Value(utils.RequestContextKeys.LoginOtpRequestKey).(AuthModels.LoginOtpRequest); ok {
+		email = loginOtpRequest.Email
+	} else if loginOtpVerifyRequest, ok := ctx.Value(utils.RequestContextKeys.LoginOtpVerifyRequestKey).(AuthModels.LoginOtpVerifyRequest); ok {
+		email = loginOtpVerifyRequest.Email
+	} else if magicLinkRequest, ok := ctx.Value(utils.RequestContextKeys.MagicLinkRequestKey).(AuthModels.MagicLinkRequest); ok {
+		email = magicLinkRequest.Email
+	} else if resendVerificationRequest, ok := ctx.Value(utils.RequestContextKeys.ResendVerificationKey).(AuthModels.ResendVerificationEmailRequest); ok {
+		email = resendVerificationRequest.Email
+	} else if forgotPasswordRequest, ok := ctx.Value(utils.RequestContextKeys.ForgotPasswordRequestKey).(AuthModels.ForgotPasswordRequest); ok {
+		email = forgotPasswordRequest.Email
+	}
+
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// RateLimitKeyByUserId counts the requests per authenticated user. AuthenticateRequest must run before the rate limiter
+func RateLimitKeyByUserId(httpRequest *http.Request) string {
+	authClaims, _ := httpRequest.Context().Value(utils.RequestContextKeys.AuthClaimsKey).(AuthModels.AuthClaims)
+	return authClaims.UserId
+}
+
+func setRateLimitHeaders(responseWriter http.ResponseWriter, policy RateLimitPolicy, result RateLimitResult) {
+	responseWriter.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	responseWriter.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	responseWriter.Header().Set("RateLimit-Reset", strconv.FormatInt(ceilSeconds(result.ResetAfter), 10))
+	responseWriter.Header().Set("RateLimit-Policy", strconv.Itoa(policy.Limit)+";w="+strconv.FormatInt(int64(policy.Window.Seconds()), 10))
+}
+
+func ceilSeconds(duration time.Duration) int64 {
+	return int64(math.Ceil(duration.Seconds()))
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	tokenService "github.com/akgarg0472/urlshortener-auth-service/internal/service/token"
+	AuthModels "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+)
+
+// AuthenticateRequest validates the JWT token provided in the Authorization header or auth_token cookie and stores
+// its claims in the request context
+func AuthenticateRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		authToken := utils.ExtractAuthToken(httpRequest)
+
+		if authToken == "" {
+			if logger.IsErrorEnabled() {
+				logger.Error("Auth token is missing",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			writeErrorResponse(responseWriter, http.StatusUnauthorized, utils.GetErrorResponseByte("Authentication required", 401))
+			return
+		}
+
+		authClaims, authError := tokenService.GetInstance().AuthenticateJwtToken(requestId, authToken)
+
+		if authError != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Auth token validation failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Any(constants.ErrorMessageLogKey, authError.Message),
+				)
+			}
+			writeErrorResponse(responseWriter, http.StatusUnauthorized, utils.GetErrorResponseByte(authError.Message, 401))
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.AuthClaimsKey, *authClaims)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
+
+// RequireAdminScope rejects authenticated requests whose token does not carry an admin scope
+func RequireAdminScope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		authClaims, ok := httpRequest.Context().Value(utils.RequestContextKeys.AuthClaimsKey).(AuthModels.AuthClaims)
+
+		if !ok || !utils.HasAdminScope(authClaims.Scopes) {
+			if logger.IsErrorEnabled() {
+				logger.Error("Admin scope not found",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			writeErrorResponse(responseWriter, http.StatusForbidden, utils.GetErrorResponseByte("Admin scope required", 403))
+			return
+		}
+
+		next.ServeHTTP(responseWriter, httpRequest)
+	})
+}
@@ -437,3 +437,633 @@ func VerifyAdminRequestBodyHandler(next http.Handler) http.Handler {
 		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
 	})
 }
+
+func RefreshTokenRequestBodyValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		var refreshTokenRequest AuthModels.RefreshTokenRequest
+
+		decodeError := decodeRequestBody(httpRequest, &refreshTokenRequest)
+
+		if decodeError != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error decoding refresh token request body",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(decodeError),
+				)
+			}
+			resp := utils.GetErrorResponse(invalidRequestBodyMessage, 400)
+			errorJsonResponse, _ := utils.ConvertToJsonBytes(resp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorJsonResponse)
+			return
+		}
+
+		validationErrors := utils.ValidateRequestFields(refreshTokenRequest)
+
+		if validationErrors != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Refresh Token Request Validation failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			errResp := AuthModels.ErrorResponse{
+				Message:   requestValidationFailedMessage,
+				ErrorCode: 400,
+				Errors:    validationErrors,
+			}
+			errorResponse, _ := json.Marshal(errResp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorResponse)
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.RefreshTokenRequestKey, refreshTokenRequest)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
+
+func AddSigningKeyRequestBodyValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		var addSigningKeyRequest AuthModels.AddSigningKeyRequest
+
+		decodeError := decodeRequestBody(httpRequest, &addSigningKeyRequest)
+
+		if decodeError != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error decoding add signing key request body",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(decodeError),
+				)
+			}
+			resp := utils.GetErrorResponse(invalidRequestBodyMessage, 400)
+			errorJsonResponse, _ := utils.ConvertToJsonBytes(resp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorJsonResponse)
+			return
+		}
+
+		validationErrors := utils.ValidateRequestFields(addSigningKeyRequest)
+
+		if validationErrors != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Add Signing Key Request Validation failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			errResp := AuthModels.ErrorResponse{
+				Message:   requestValidationFailedMessage,
+				ErrorCode: 400,
+				Errors:    validationErrors,
+			}
+			errorResponse, _ := json.Marshal(errResp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorResponse)
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.AddSigningKeyRequestKey, addSigningKeyRequest)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
+
+func RetireSigningKeyRequestBodyValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		var retireSigningKeyRequest AuthModels.RetireSigningKeyRequest
+
+		decodeError := decodeRequestBody(httpRequest, &retireSigningKeyRequest)
+
+		if decodeError != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error decoding retire signing key request body",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(decodeError),
+				)
+			}
+			resp := utils.GetErrorResponse(invalidRequestBodyMessage, 400)
+			errorJsonResponse, _ := utils.ConvertToJsonBytes(resp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorJsonResponse)
+			return
+		}
+
+		validationErrors := utils.ValidateRequestFields(retireSigningKeyRequest)
+
+		if validationErrors != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Retire Signing Key Request Validation failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			errResp := AuthModels.ErrorResponse{
+				Message:   requestValidationFailedMessage,
+				ErrorCode: 400,
+				Errors:    validationErrors,
+			}
+			errorResponse, _ := json.Marshal(errResp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorResponse)
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.RetireSigningKeyRequestKey, retireSigningKeyRequest)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
+
+func TotpCodeRequestBodyValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		var totpCodeRequest AuthModels.TotpCodeRequest
+
+		decodeError := decodeRequestBody(httpRequest, &totpCodeRequest)
+
+		if decodeError != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error decoding totp code request body",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(decodeError),
+				)
+			}
+			resp := utils.GetErrorResponse(invalidRequestBodyMessage, 400)
+			errorJsonResponse, _ := utils.ConvertToJsonBytes(resp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorJsonResponse)
+			return
+		}
+
+		validationErrors := utils.ValidateRequestFields(totpCodeRequest)
+
+		if validationErrors != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("TOTP Code Request Validation failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			errResp := AuthModels.ErrorResponse{
+				Message:   requestValidationFailedMessage,
+				ErrorCode: 400,
+				Errors:    validationErrors,
+			}
+			errorResponse, _ := json.Marshal(errResp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorResponse)
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.TotpCodeRequestKey, totpCodeRequest)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
+
+func MfaLoginRequestBodyValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		var mfaLoginRequest AuthModels.MfaLoginRequest
+
+		decodeError := decodeRequestBody(httpRequest, &mfaLoginRequest)
+
+		if decodeError != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error decoding mfa login request body",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(decodeError),
+				)
+			}
+			resp := utils.GetErrorResponse(invalidRequestBodyMessage, 400)
+			errorJsonResponse, _ := utils.ConvertToJsonBytes(resp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorJsonResponse)
+			return
+		}
+
+		validationErrors := utils.ValidateRequestFields(mfaLoginRequest)
+
+		if validationErrors != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("MFA Login Request Validation failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			errResp := AuthModels.ErrorResponse{
+				Message:   requestValidationFailedMessage,
+				ErrorCode: 400,
+				Errors:    validationErrors,
+			}
+			errorResponse, _ := json.Marshal(errResp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorResponse)
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.MfaLoginRequestKey, mfaLoginRequest)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
+
+func LoginOtpRequestBodyValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		var loginOtpRequest AuthModels.LoginOtpRequest
+
+		decodeError := decodeRequestBody(httpRequest, &loginOtpRequest)
+
+		if decodeError != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error decoding login otp request body",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(decodeError),
+				)
+			}
+			resp := utils.GetErrorResponse(invalidRequestBodyMessage, 400)
+			errorJsonResponse, _ := utils.ConvertToJsonBytes(resp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorJsonResponse)
+			return
+		}
+
+		validationErrors := utils.ValidateRequestFields(loginOtpRequest)
+
+		if validationErrors != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Login OTP Request Validation failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			errResp := AuthModels.ErrorResponse{
+				Message:   requestValidationFailedMessage,
+				ErrorCode: 400,
+				Errors:    validationErrors,
+			}
+			errorResponse, _ := json.Marshal(errResp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorResponse)
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.LoginOtpRequestKey, loginOtpRequest)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
+
+func LoginOtpVerifyRequestBodyValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		var loginOtpVerifyRequest AuthModels.LoginOtpVerifyRequest
+
+		decodeError := decodeRequestBody(httpRequest, &loginOtpVerifyRequest)
+
+		if decodeError != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error decoding login otp verify request body",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(decodeError),
+				)
+			}
+			resp := utils.GetErrorResponse(invalidRequestBodyMessage, 400)
+			errorJsonResponse, _ := utils.ConvertToJsonBytes(resp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorJsonResponse)
+			return
+		}
+
+		validationErrors := utils.ValidateRequestFields(loginOtpVerifyRequest)
+
+		if validationErrors != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Login OTP Verify Request Validation failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			errResp := AuthModels.ErrorResponse{
+				Message:   requestValidationFailedMessage,
+				ErrorCode: 400,
+				Errors:    validationErrors,
+			}
+			errorResponse, _ := json.Marshal(errResp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorResponse)
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.LoginOtpVerifyRequestKey, loginOtpVerifyRequest)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
+
+func MagicLinkRequestBodyValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		var magicLinkRequest AuthModels.MagicLinkRequest
+
+		decodeError := decodeRequestBody(httpRequest, &magicLinkRequest)
+
+		if decodeError != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error decoding magic link request body",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(decodeError),
+				)
+			}
+			resp := utils.GetErrorResponse(invalidRequestBodyMessage, 400)
+			errorJsonResponse, _ := utils.ConvertToJsonBytes(resp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorJsonResponse)
+			return
+		}
+
+		validationErrors := utils.ValidateRequestFields(magicLinkRequest)
+
+		if validationErrors != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Magic Link Request Validation failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			errResp := AuthModels.ErrorResponse{
+				Message:   requestValidationFailedMessage,
+				ErrorCode: 400,
+				Errors:    validationErrors,
+			}
+			errorResponse, _ := json.Marshal(errResp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorResponse)
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.MagicLinkRequestKey, magicLinkRequest)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
+
+func ResendVerificationEmailRequestBodyValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		var resendVerificationRequest AuthModels.ResendVerificationEmailRequest
+
+		decodeError := decodeRequestBody(httpRequest, &resendVerificationRequest)
+
+		if decodeError != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error decoding resend verification email request body",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(decodeError),
+				)
+			}
+			resp := utils.GetErrorResponse(invalidRequestBodyMessage, 400)
+			errorJsonResponse, _ := utils.ConvertToJsonBytes(resp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorJsonResponse)
+			return
+		}
+
+		validationErrors := utils.ValidateRequestFields(resendVerificationRequest)
+
+		if validationErrors != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Resend Verification Email Request Validation failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			errResp := AuthModels.ErrorResponse{
+				Message:   requestValidationFailedMessage,
+				ErrorCode: 400,
+				Errors:    validationErrors,
+			}
+			errorResponse, _ := json.Marshal(errResp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorResponse)
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.ResendVerificationKey, resendVerificationRequest)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
+
+func ChangePasswordRequestBodyValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		var changePasswordRequest AuthModels.ChangePasswordRequest
+
+		decodeError := decodeRequestBody(httpRequest, &changePasswordRequest)
+
+		if decodeError != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error decoding change password request body",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(decodeError),
+				)
+			}
+			resp := utils.GetErrorResponse(invalidRequestBodyMessage, 400)
+			errorJsonResponse, _ := utils.ConvertToJsonBytes(resp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorJsonResponse)
+			return
+		}
+
+		validationErrors := utils.ValidateRequestFields(changePasswordRequest)
+
+		if validationErrors != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Change Password Request Validation failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			errResp := AuthModels.ErrorResponse{
+				Message:   requestValidationFailedMessage,
+				ErrorCode: 400,
+				Errors:    validationErrors,
+			}
+			errorResponse, _ := json.Marshal(errResp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorResponse)
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.ChangePasswordRequestKey, changePasswordRequest)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
+
+func LinkOAuthIdentityRequestBodyValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		var linkOAuthIdentityRequest AuthModels.LinkOAuthIdentityRequest
+
+		decodeError := decodeRequestBody(httpRequest, &linkOAuthIdentityRequest)
+
+		if decodeError != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error decoding link oauth identity request body",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(decodeError),
+				)
+			}
+			resp := utils.GetErrorResponse(invalidRequestBodyMessage, 400)
+			errorJsonResponse, _ := utils.ConvertToJsonBytes(resp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorJsonResponse)
+			return
+		}
+
+		validationErrors := utils.ValidateRequestFields(linkOAuthIdentityRequest)
+
+		if validationErrors != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Link OAuth Identity Request Validation failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			errResp := AuthModels.ErrorResponse{
+				Message:   requestValidationFailedMessage,
+				ErrorCode: 400,
+				Errors:    validationErrors,
+			}
+			errorResponse, _ := json.Marshal(errResp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorResponse)
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.LinkOAuthIdentityRequestKey, linkOAuthIdentityRequest)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
+
+func UpdateProfileRequestBodyValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		var updateProfileRequest AuthModels.UpdateProfileRequest
+
+		decodeError := decodeRequestBody(httpRequest, &updateProfileRequest)
+
+		if decodeError != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error decoding update profile request body",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(decodeError),
+				)
+			}
+			resp := utils.GetErrorResponse(invalidRequestBodyMessage, 400)
+			errorJsonResponse, _ := utils.ConvertToJsonBytes(resp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorJsonResponse)
+			return
+		}
+
+		validationErrors := utils.ValidateRequestFields(updateProfileRequest)
+
+		if validationErrors != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Update Profile Request Validation failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			errResp := AuthModels.ErrorResponse{
+				Message:   requestValidationFailedMessage,
+				ErrorCode: 400,
+				Errors:    validationErrors,
+			}
+			errorResponse, _ := json.Marshal(errResp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorResponse)
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.UpdateProfileRequestKey, updateProfileRequest)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
+
+func DeleteAccountRequestBodyValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		var deleteAccountRequest AuthModels.DeleteAccountRequest
+
+		decodeError := decodeRequestBody(httpRequest, &deleteAccountRequest)
+
+		if decodeError != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error decoding delete account request body",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(decodeError),
+				)
+			}
+			resp := utils.GetErrorResponse(invalidRequestBodyMessage, 400)
+			errorJsonResponse, _ := utils.ConvertToJsonBytes(resp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorJsonResponse)
+			return
+		}
+
+		validationErrors := utils.ValidateRequestFields(deleteAccountRequest)
+
+		if validationErrors != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Delete Account Request Validation failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			errResp := AuthModels.ErrorResponse{
+				Message:   requestValidationFailedMessage,
+				ErrorCode: 400,
+				Errors:    validationErrors,
+			}
+			errorResponse, _ := json.Marshal(errResp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorResponse)
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.DeleteAccountRequestKey, deleteAccountRequest)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
+
+func ChangeEmailRequestBodyValidator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, httpRequest *http.Request) {
+		requestId := httpRequest.Header.Get(constants.RequestIdHeaderName)
+
+		var changeEmailRequest AuthModels.ChangeEmailRequest
+
+		decodeError := decodeRequestBody(httpRequest, &changeEmailRequest)
+
+		if decodeError != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error decoding change email request body",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(decodeError),
+				)
+			}
+			resp := utils.GetErrorResponse(invalidRequestBodyMessage, 400)
+			errorJsonResponse, _ := utils.ConvertToJsonBytes(resp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorJsonResponse)
+			return
+		}
+
+		validationErrors := utils.ValidateRequestFields(changeEmailRequest)
+
+		if validationErrors != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Change Email Request Validation failed",
+					zap.String(constants.RequestIdLogKey, requestId),
+				)
+			}
+			errResp := AuthModels.ErrorResponse{
+				Message:   requestValidationFailedMessage,
+				ErrorCode: 400,
+				Errors:    validationErrors,
+			}
+			errorResponse, _ := json.Marshal(errResp)
+			writeErrorResponse(responseWriter, http.StatusBadRequest, errorResponse)
+			return
+		}
+
+		ctx := context.WithValue(httpRequest.Context(), utils.RequestContextKeys.ChangeEmailRequestKey, changeEmailRequest)
+
+		next.ServeHTTP(responseWriter, httpRequest.WithContext(ctx))
+	})
+}
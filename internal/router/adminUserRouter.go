@@ -0,0 +1,20 @@
+package router
+
+import (
+	"github.com/go-chi/chi"
+
+	"github.com/akgarg0472/urlshortener-auth-service/internal/handler"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/middleware"
+)
+
+func AdminUserRouterV1() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Use(middleware.AddRequestIdHeader)
+	router.Use(middleware.AuthenticateRequest)
+	router.Use(middleware.RequireAdminScope)
+
+	router.Post("/{userId}/unlock", handler.UnlockAccountHandler)
+
+	return router
+}
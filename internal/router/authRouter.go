@@ -1,6 +1,8 @@
 package router
 
 import (
+	"time"
+
 	"github.com/go-chi/chi"
 
 	"github.com/akgarg0472/urlshortener-auth-service/internal/handler"
@@ -10,20 +12,111 @@ import (
 func AuthRouterV1() *chi.Mux {
 	router := chi.NewRouter()
 
+	loginIpRateLimitPolicy := middleware.NewRateLimitPolicy("login-ip", 20, time.Minute, middleware.RateLimitKeyByIp)
+	loginEmailRateLimitPolicy := middleware.NewRateLimitPolicy("login-email", 10, time.Minute, middleware.RateLimitKeyByEmail)
+	signupIpRateLimitPolicy := middleware.NewRateLimitPolicy("signup-ip", 5, time.Hour, middleware.RateLimitKeyByIp)
+	forgotPasswordIpRateLimitPolicy := middleware.NewRateLimitPolicy("forgot-password-ip", 10, time.Hour, middleware.RateLimitKeyByIp)
+	loginOtpIpRateLimitPolicy := middleware.NewRateLimitPolicy("login-otp-ip", 10, time.Hour, middleware.RateLimitKeyByIp)
+	loginOtpEmailRateLimitPolicy := middleware.NewRateLimitPolicy("login-otp-email", 3, 15*time.Minute, middleware.RateLimitKeyByEmail)
+	magicLinkIpRateLimitPolicy := middleware.NewRateLimitPolicy("magic-link-ip", 10, time.Hour, middleware.RateLimitKeyByIp)
+	magicLinkEmailRateLimitPolicy := middleware.NewRateLimitPolicy("magic-link-email", 3, 15*time.Minute, middleware.RateLimitKeyByEmail)
+	verifyEmailIpRateLimitPolicy := middleware.NewRateLimitPolicy("verify-email-ip", 10, time.Hour, middleware.RateLimitKeyByIp)
+	verifyEmailEmailRateLimitPolicy := middleware.NewRateLimitPolicy("verify-email-email", 3, time.Hour, middleware.RateLimitKeyByEmail)
+	forgotPasswordEmailRateLimitPolicy := middleware.NewRateLimitPolicy("forgot-password-email", 3, time.Hour, middleware.RateLimitKeyByEmail)
+	passwordSetupIpRateLimitPolicy := middleware.NewRateLimitPolicy("password-setup-ip", 3, time.Hour, middleware.RateLimitKeyByIp)
+	refreshIpRateLimitPolicy := middleware.NewRateLimitPolicy("refresh-ip", 60, time.Minute, middleware.RateLimitKeyByIp)
+
 	router.Route("/login", func(r chi.Router) {
 		r.Use(middleware.AddRequestIdHeader)
 		r.Use(middleware.ValidateRequestJSONContentType)
+		r.Use(middleware.RateLimit(loginIpRateLimitPolicy))
 		r.Use(middleware.LoginRequestBodyValidator)
+		r.Use(middleware.RateLimit(loginEmailRateLimitPolicy))
 		r.Post("/", handler.LoginHandler)
 	})
 
+	router.Route("/login/mfa", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Use(middleware.ValidateRequestJSONContentType)
+		r.Use(middleware.RateLimit(loginIpRateLimitPolicy))
+		r.Use(middleware.MfaLoginRequestBodyValidator)
+		r.Post("/", handler.MfaLoginHandler)
+	})
+
+	router.Route("/login/otp/request", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Use(middleware.ValidateRequestJSONContentType)
+		r.Use(middleware.RateLimit(loginOtpIpRateLimitPolicy))
+		r.Use(middleware.LoginOtpRequestBodyValidator)
+		r.Use(middleware.RateLimit(loginOtpEmailRateLimitPolicy))
+		r.Post("/", handler.LoginOtpRequestHandler)
+	})
+
+	router.Route("/login/otp/verify", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Use(middleware.ValidateRequestJSONContentType)
+		r.Use(middleware.RateLimit(loginIpRateLimitPolicy))
+		r.Use(middleware.LoginOtpVerifyRequestBodyValidator)
+		r.Use(middleware.RateLimit(loginEmailRateLimitPolicy))
+		r.Post("/", handler.LoginOtpVerifyHandler)
+	})
+
+	router.Route("/login/magic-link", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.ValidateRequestJSONContentType)
+			r.Use(middleware.RateLimit(magicLinkIpRateLimitPolicy))
+			r.Use(middleware.MagicLinkRequestBodyValidator)
+			r.Use(middleware.RateLimit(magicLinkEmailRateLimitPolicy))
+			r.Post("/", handler.MagicLinkRequestHandler)
+		})
+
+		r.With(middleware.RateLimit(loginIpRateLimitPolicy)).Get("/verify", handler.VerifyMagicLinkHandler)
+	})
+
+	router.Route("/mfa/totp", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Use(middleware.AuthenticateRequest)
+		r.Post("/enroll", handler.EnrollTotpHandler)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.ValidateRequestJSONContentType)
+			r.Use(middleware.TotpCodeRequestBodyValidator)
+			r.Post("/confirm", handler.ConfirmTotpHandler)
+			r.Post("/disable", handler.DisableTotpHandler)
+		})
+	})
+
+	router.Route("/refresh", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Use(middleware.ValidateRequestJSONContentType)
+		r.Use(middleware.RateLimit(refreshIpRateLimitPolicy))
+		r.Use(middleware.RefreshTokenRequestBodyValidator)
+		r.Post("/", handler.RefreshTokenHandler)
+	})
+
 	router.Route("/signup", func(r chi.Router) {
 		r.Use(middleware.AddRequestIdHeader)
 		r.Use(middleware.ValidateRequestJSONContentType)
+		r.Use(middleware.RateLimit(signupIpRateLimitPolicy))
 		r.Use(middleware.SignupRequestBodyValidator)
 		r.Post("/", handler.SignupHandler)
 	})
 
+	router.Route("/verify-email", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Get("/", handler.VerifyEmailHandler)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.ValidateRequestJSONContentType)
+			r.Use(middleware.RateLimit(verifyEmailIpRateLimitPolicy))
+			r.Use(middleware.ResendVerificationEmailRequestBodyValidator)
+			r.Use(middleware.RateLimit(verifyEmailEmailRateLimitPolicy))
+			r.Post("/resend", handler.ResendVerificationEmailHandler)
+		})
+	})
+
 	router.Route("/validate-token", func(r chi.Router) {
 		r.Use(middleware.AddRequestIdHeader)
 		r.Use(middleware.ValidateRequestJSONContentType)
@@ -41,7 +134,9 @@ func AuthRouterV1() *chi.Mux {
 	router.Route("/forgot-password", func(r chi.Router) {
 		r.Use(middleware.AddRequestIdHeader)
 		r.Use(middleware.ValidateRequestJSONContentType)
+		r.Use(middleware.RateLimit(forgotPasswordIpRateLimitPolicy))
 		r.Use(middleware.ForgotPasswordRequestBodyValidator)
+		r.Use(middleware.RateLimit(forgotPasswordEmailRateLimitPolicy))
 		r.Post("/", handler.ForgotPasswordHandler)
 	})
 
@@ -57,6 +152,28 @@ func AuthRouterV1() *chi.Mux {
 		r.Post("/", handler.ResetPasswordHandler)
 	})
 
+	router.Route("/change-password", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Use(middleware.AuthenticateRequest)
+		r.Use(middleware.ValidateRequestJSONContentType)
+		r.Use(middleware.ChangePasswordRequestBodyValidator)
+		r.Post("/", handler.ChangePasswordHandler)
+	})
+
+	router.Route("/password/setup", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Use(middleware.AuthenticateRequest)
+		r.Use(middleware.RateLimit(passwordSetupIpRateLimitPolicy))
+		r.Post("/", handler.PasswordSetupHandler)
+	})
+
+	router.Route("/sessions", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Use(middleware.AuthenticateRequest)
+		r.Get("/", handler.ListSessionsHandler)
+		r.Delete("/{id}", handler.RevokeSessionHandler)
+	})
+
 	router.Route("/verify-admin", func(r chi.Router) {
 		r.Use(middleware.AddRequestIdHeader)
 		r.Use(middleware.ValidateRequestJSONContentType)
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"github.com/go-chi/chi"
+
+	"github.com/akgarg0472/urlshortener-auth-service/internal/handler"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/middleware"
+)
+
+func IntrospectionRouterV1() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Route("/introspect", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Use(middleware.AuthenticateIntrospectionClient)
+		r.Post("/", handler.IntrospectionHandler)
+	})
+
+	return router
+}
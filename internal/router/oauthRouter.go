@@ -1,6 +1,8 @@
 package router
 
 import (
+	"time"
+
 	"github.com/go-chi/chi"
 
 	"github.com/akgarg0472/urlshortener-auth-service/internal/handler"
@@ -10,11 +12,39 @@ import (
 func OAuthRouterV1() *chi.Mux {
 	router := chi.NewRouter()
 
+	authorizeIpRateLimitPolicy := middleware.NewRateLimitPolicy("oauth-authorize-ip", 30, time.Minute, middleware.RateLimitKeyByIp)
+
 	router.Route("/providers", func(r chi.Router) {
 		r.Use(middleware.AddRequestIdHeader)
 		r.Get("/", handler.GetOAuthProvidersHandler)
 	})
 
+	router.Route("/authorize", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Use(middleware.RateLimit(authorizeIpRateLimitPolicy))
+		r.Get("/{provider}", handler.OAuthAuthorizeHandler)
+	})
+
+	router.Route("/identities", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Use(middleware.AuthenticateRequest)
+		r.Get("/", handler.ListOAuthIdentitiesHandler)
+		r.Delete("/{id}", handler.UnlinkOAuthIdentityHandler)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.ValidateRequestJSONContentType)
+			r.Use(middleware.RateLimit(authorizeIpRateLimitPolicy))
+			r.Use(middleware.LinkOAuthIdentityRequestBodyValidator)
+			r.Post("/link/{provider}", handler.StartOAuthIdentityLinkHandler)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.ValidateRequestJSONContentType)
+			r.Use(middleware.OAuthCallbackRequestBodyValidator)
+			r.Post("/", handler.LinkOAuthIdentityHandler)
+		})
+	})
+
 	router.Route("/callbacks", func(r chi.Router) {
 		r.Use(middleware.AddRequestIdHeader)
 		r.Use(middleware.ValidateRequestJSONContentType)
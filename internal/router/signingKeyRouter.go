@@ -0,0 +1,34 @@
+package router
+
+import (
+	"github.com/go-chi/chi"
+
+	"github.com/akgarg0472/urlshortener-auth-service/internal/handler"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/middleware"
+)
+
+func SigningKeyRouterV1() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Use(middleware.AddRequestIdHeader)
+	router.Use(middleware.AuthenticateRequest)
+	router.Use(middleware.RequireAdminScope)
+
+	router.Get("/", handler.ListSigningKeysHandler)
+
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.ValidateRequestJSONContentType)
+		r.Use(middleware.AddSigningKeyRequestBodyValidator)
+		r.Post("/", handler.AddSigningKeyHandler)
+	})
+
+	router.Post("/{kid}/promote", handler.PromoteSigningKeyHandler)
+
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.ValidateRequestJSONContentType)
+		r.Use(middleware.RetireSigningKeyRequestBodyValidator)
+		r.Post("/{kid}/retire", handler.RetireSigningKeyHandler)
+	})
+
+	return router
+}
@@ -0,0 +1,77 @@
+package router
+
+import (
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"github.com/akgarg0472/urlshortener-auth-service/internal/handler"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/middleware"
+)
+
+func UserRouterV1() *chi.Mux {
+	router := chi.NewRouter()
+
+	reauthenticateIpRateLimitPolicy := middleware.NewRateLimitPolicy("reauthenticate-ip", 30, time.Minute, middleware.RateLimitKeyByIp)
+	dataExportIpRateLimitPolicy := middleware.NewRateLimitPolicy("data-export-ip", 5, time.Hour, middleware.RateLimitKeyByIp)
+	dataExportDownloadIpRateLimitPolicy := middleware.NewRateLimitPolicy("data-export-download-ip", 20, time.Hour, middleware.RateLimitKeyByIp)
+	changeEmailIpRateLimitPolicy := middleware.NewRateLimitPolicy("change-email-ip", 5, time.Hour, middleware.RateLimitKeyByIp)
+	emailChangeLinkIpRateLimitPolicy := middleware.NewRateLimitPolicy("email-change-link-ip", 10, time.Hour, middleware.RateLimitKeyByIp)
+	restoreAccountIpRateLimitPolicy := middleware.NewRateLimitPolicy("restore-account-ip", 10, time.Hour, middleware.RateLimitKeyByIp)
+
+	router.Route("/me", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Use(middleware.AuthenticateRequest)
+		r.Get("/", handler.GetProfileHandler)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.ValidateRequestJSONContentType)
+			r.Use(middleware.UpdateProfileRequestBodyValidator)
+			r.Patch("/", handler.UpdateProfileHandler)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.ValidateRequestJSONContentType)
+			r.Use(middleware.DeleteAccountRequestBodyValidator)
+			r.Delete("/", handler.DeleteAccountHandler)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RateLimit(reauthenticateIpRateLimitPolicy))
+			r.Post("/reauthenticate/{provider}", handler.StartReauthenticationHandler)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RateLimit(dataExportIpRateLimitPolicy))
+			r.Get("/export", handler.ExportDataHandler)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RateLimit(changeEmailIpRateLimitPolicy))
+			r.Use(middleware.ValidateRequestJSONContentType)
+			r.Use(middleware.ChangeEmailRequestBodyValidator)
+			r.Post("/email", handler.RequestEmailChangeHandler)
+		})
+	})
+
+	router.Route("/restore", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Use(middleware.RateLimit(restoreAccountIpRateLimitPolicy))
+		r.Get("/", handler.RestoreAccountHandler)
+	})
+
+	router.Route("/email", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Use(middleware.RateLimit(emailChangeLinkIpRateLimitPolicy))
+		r.Get("/confirm", handler.ConfirmEmailChangeHandler)
+		r.Get("/cancel", handler.CancelEmailChangeHandler)
+	})
+
+	router.Route("/export/download", func(r chi.Router) {
+		r.Use(middleware.AddRequestIdHeader)
+		r.Use(middleware.RateLimit(dataExportDownloadIpRateLimitPolicy))
+		r.Get("/", handler.DownloadDataExportHandler)
+	})
+
+	return router
+}
@@ -0,0 +1,17 @@
+package router
+
+import (
+	"github.com/go-chi/chi"
+
+	"github.com/akgarg0472/urlshortener-auth-service/internal/handler"
+)
+
+func WellKnownRouterV1() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Route("/jwks.json", func(r chi.Router) {
+		r.Get("/", handler.JWKSHandler)
+	})
+
+	return router
+}
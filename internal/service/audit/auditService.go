@@ -0,0 +1,53 @@
+package audit_service
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	auditDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/audit"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RecordEvent writes the audit record of the security relevant event. The userId is empty if the event can't be tied
+// to a user. Failures are only logged so that auditing never breaks the audited flow
+func RecordEvent(
+	requestId string,
+	event constants.AuditEvent,
+	userId string,
+	clientInfo model.ClientInfo,
+	details map[string]string,
+) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Recording audit event",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("event", string(event)),
+			zap.String("userId", userId),
+		)
+	}
+
+	auditLog := &entity.AuditLog{
+		Id:        strings.ReplaceAll(uuid.New().String(), "-", ""),
+		Event:     event,
+		RequestId: requestId,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
+	}
+
+	if userId != "" {
+		auditLog.UserId = &userId
+	}
+
+	if len(details) > 0 {
+		if encodedDetails, err := json.Marshal(details); err == nil {
+			detailsJson := string(encodedDetails)
+			auditLog.Details = &detailsJson
+		}
+	}
+
+	_ = auditDao.SaveAuditLog(requestId, auditLog)
+}
@@ -4,26 +4,36 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/akgarg0472/urlshortener-auth-service/constants"
 	"github.com/akgarg0472/urlshortener-auth-service/model"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
-	"golang.org/x/crypto/bcrypt"
-
 	authDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/auth"
+	identityDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/identity"
+	sessionDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/session"
 	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
 	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	auditService "github.com/akgarg0472/urlshortener-auth-service/internal/service/audit"
 	kafka_service "github.com/akgarg0472/urlshortener-auth-service/internal/service/kafka"
+	mfaService "github.com/akgarg0472/urlshortener-auth-service/internal/service/mfa"
 	notificationService "github.com/akgarg0472/urlshortener-auth-service/internal/service/notification"
+	passwordService "github.com/akgarg0472/urlshortener-auth-service/internal/service/password"
+	sessionService "github.com/akgarg0472/urlshortener-auth-service/internal/service/session"
+	throttleService "github.com/akgarg0472/urlshortener-auth-service/internal/service/throttle"
 	tokenService "github.com/akgarg0472/urlshortener-auth-service/internal/service/token"
 	authModels "github.com/akgarg0472/urlshortener-auth-service/model"
 	"github.com/akgarg0472/urlshortener-auth-service/utils"
 )
 
 // LoginWithEmailPassword Function to handle login request using email & password and generate JWT token
-func LoginWithEmailPassword(requestId string, loginRequest authModels.LoginRequest) (*authModels.LoginResponse, *authModels.ErrorResponse) {
+func LoginWithEmailPassword(
+	requestId string,
+	loginRequest authModels.LoginRequest,
+	clientInfo authModels.ClientInfo,
+) (*authModels.LoginResponse, *authModels.ErrorResponse) {
 	if logger.IsInfoEnabled() {
 		logger.Info(
 			"Processing LoginWithEmailPassword Request",
@@ -32,6 +42,12 @@ func LoginWithEmailPassword(requestId string, loginRequest authModels.LoginReque
 		)
 	}
 
+	ipThrottleKey := throttleService.IpThrottleKey(clientInfo.IpAddress)
+
+	if throttleError := throttleService.CheckLoginAllowed(requestId, ipThrottleKey, false); throttleError != nil {
+		return nil, throttleError
+	}
+
 	user, err := authDao.GetUserByEmail(requestId, loginRequest.Email)
 
 	if err != nil {
@@ -45,6 +61,7 @@ func LoginWithEmailPassword(requestId string, loginRequest authModels.LoginReque
 		}
 
 		if err.ErrorCode == 404 {
+			_, _ = throttleService.RecordFailedLogin(requestId, ipThrottleKey, false)
 			return nil, &authModels.ErrorResponse{
 				Message:   "Invalid credentials",
 				ErrorCode: 401,
@@ -62,30 +79,133 @@ func LoginWithEmailPassword(requestId string, loginRequest authModels.LoginReque
 		)
 	}
 
-	if user.LoginType != constants.UserEntityLoginTypeEmailAndPassword {
+	if !user.LoginType.AllowsPassword() {
 		if logger.IsInfoEnabled() {
 			logger.Info(
-				"User is not registered using email and password",
+				"User is not allowed to log in using password",
 				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("loginType", string(user.LoginType)),
 			)
 		}
+		linkedProviders := describeLinkedProviders(requestId, user.Id)
 		return nil, &authModels.ErrorResponse{
-			Message:   fmt.Sprintf("Your account is registered using %s OAuth and does not have a password. Please log in using %s OAuth.", user.OAuthProvider, user.OAuthProvider),
+			Message:   fmt.Sprintf("Your account is registered using %s and does not have a password. Please log in using %s, or use forgot password to set one.", linkedProviders, linkedProviders),
 			ErrorCode: 400,
 		}
 	}
 
-	if !verifyPassword(loginRequest.Password, user.Password) {
+	passwordValid, throttleError := throttleService.VerifyPasswordWithThrottle(requestId, *user, loginRequest.Password, clientInfo)
+
+	if throttleError != nil {
+		return nil, throttleError
+	}
+
+	if !passwordValid {
+		return nil, &authModels.ErrorResponse{Message: "Invalid credentials", ErrorCode: 401}
+	}
+
+	rehashPasswordIfNeeded(requestId, *user, loginRequest.Password)
+
+	if !user.EmailVerified && !isUnverifiedEmailLoginAllowed() {
+		if logger.IsInfoEnabled() {
+			logger.Info(
+				"User email is not verified",
+				zap.String(constants.RequestIdLogKey, requestId),
+			)
+		}
+		return nil, utils.GetErrorResponse("Please verify your email before logging in", 403)
+	}
+
+	return CompleteLogin(requestId, *user, constants.SessionLoginMethodEmailPassword, clientInfo)
+}
+
+// LoginWithMfa Function to complete the login of a user with MFA enabled by verifying the TOTP or recovery code
+func LoginWithMfa(
+	requestId string,
+	mfaLoginRequest authModels.MfaLoginRequest,
+	clientInfo authModels.ClientInfo,
+) (*authModels.LoginResponse, *authModels.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing LoginWithMfa Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	userId, loginMethod, err := tokenService.GetInstance().ValidateMfaChallengeToken(requestId, mfaLoginRequest.MfaToken)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accountThrottleKey := throttleService.AccountThrottleKey(userId)
+
+	if throttleError := throttleService.CheckLoginAllowed(requestId, accountThrottleKey, true); throttleError != nil {
+		return nil, throttleError
+	}
+
+	user, err := authDao.GetUserById(requestId, userId)
+
+	if err != nil {
+		if err.ErrorCode == 404 {
+			return nil, utils.GetErrorResponse("Invalid or expired MFA token", 401)
+		}
+		return nil, err
+	}
+
+	if mfaError := mfaService.VerifyMfaCode(requestId, user.Id, mfaLoginRequest.Code); mfaError != nil {
+		if mfaError.ErrorCode == 401 {
+			throttleService.RecordFailedLoginAttempt(requestId, *user, clientInfo.IpAddress)
+		}
+		return nil, mfaError
+	}
+
+	throttleService.ResetLoginThrottle(requestId, accountThrottleKey)
+
+	return startLoginSession(requestId, *user, loginMethod, clientInfo)
+}
+
+// RefreshAccessToken Function to rotate the refresh token and issue a new access token for its owner
+func RefreshAccessToken(requestId string, refreshTokenRequest authModels.RefreshTokenRequest) (*authModels.RefreshTokenResponse, *authModels.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Refresh Token Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	consumedToken, refreshToken, err := tokenService.GetInstance().RotateRefreshToken(requestId, refreshTokenRequest.RefreshToken)
+
+	if err != nil {
 		if logger.IsErrorEnabled() {
 			logger.Error(
-				"Invalid credentials provided",
+				"Error rotating refresh token",
 				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Int16(constants.ErrorCodeLogKey, err.ErrorCode),
+				zap.Any(constants.ErrorMessageLogKey, err.Message),
 			)
 		}
-		return nil, &authModels.ErrorResponse{Message: "Invalid credentials", ErrorCode: 401}
+		return nil, err
+	}
+
+	user, err := authDao.GetUserById(requestId, consumedToken.UserId)
+
+	if err != nil {
+		if err.ErrorCode == 404 {
+			return nil, utils.GetErrorResponse("Invalid refresh token", 401)
+		}
+		return nil, err
 	}
 
-	jwtToken, jwtError := tokenService.GetInstance().GenerateJwtToken(requestId, *user)
+	// refresh token families created before sessions existed are not bound to any session
+	sessionId := ""
+
+	if _, sessionError := sessionDao.GetSessionById(requestId, consumedToken.FamilyId); sessionError == nil {
+		sessionId = consumedToken.FamilyId
+		sessionService.TouchSession(requestId, sessionId)
+	}
+
+	jwtToken, jwtError := tokenService.GetInstance().GenerateJwtToken(requestId, *user, sessionId)
 
 	if jwtError != nil {
 		if logger.IsErrorEnabled() {
@@ -99,14 +219,10 @@ func LoginWithEmailPassword(requestId string, loginRequest authModels.LoginReque
 		return nil, jwtError
 	}
 
-	authDao.UpdateTimestamp(requestId, loginRequest.Email, authDao.TimestampTypeLastLoginTime)
-
-	return &authModels.LoginResponse{
-		AccessToken: jwtToken,
-		UserId:      user.Id,
-		Name:        user.Name,
-		Email:       user.Email,
-		LoginType:   string(user.LoginType),
+	return &authModels.RefreshTokenResponse{
+		AccessToken:  jwtToken,
+		RefreshToken: refreshToken,
+		UserId:       user.Id,
 	}, nil
 }
 
@@ -154,20 +270,24 @@ func Signup(requestId string, signupRequest authModels.SignupRequest) (*authMode
 		return nil, utils.GetErrorResponse("Email already registered", 409)
 	}
 
-	hashedPassword, bcryptError := bcrypt.GenerateFromPassword([]byte(signupRequest.Password), 14)
+	if policyError := passwordService.ValidatePassword(requestId, signupRequest.Password, signupRequest.Email, signupRequest.Name); policyError != nil {
+		return nil, policyError
+	}
+
+	hashedPassword, hashError := passwordService.HashPassword(signupRequest.Password)
 
-	if bcryptError != nil {
+	if hashError != nil {
 		if logger.IsErrorEnabled() {
 			logger.Error(
 				"Error hashing password",
 				zap.String(constants.RequestIdLogKey, requestId),
-				zap.Error(bcryptError),
+				zap.Error(hashError),
 			)
 		}
 		return nil, utils.InternalServerErrorResponse()
 	}
 
-	signupRequest.Password = string(hashedPassword)
+	signupRequest.Password = hashedPassword
 	dbUser := createUserEntity(signupRequest)
 	dbUser.UserLoginType = constants.UserEntityLoginTypeEmailAndPassword
 	user, saveError := authDao.SaveUser(requestId, dbUser)
@@ -194,14 +314,26 @@ func Signup(requestId string, signupRequest authModels.SignupRequest) (*authMode
 		return nil, utils.InternalServerErrorResponse()
 	}
 
-	if user.Email != nil {
-		notificationService.SendSignupSuccessEmail(requestId, *user.Email, user.Name)
-	}
+	passwordService.RecordPasswordHistory(requestId, user.Id, *user.Password)
 
 	kafka_service.GetInstance().PushUserRegisteredEvent(requestId, user.Id)
 
+	// the welcome email is sent once the email is verified
+	verificationError := sendEmailVerificationLink(requestId, authModels.User{Id: user.Id, Name: user.Name, Email: *user.Email})
+
+	if verificationError != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error sending email verification link",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Int16(constants.ErrorCodeLogKey, verificationError.ErrorCode),
+				zap.Any(constants.ErrorMessageLogKey, verificationError.Message),
+			)
+		}
+	}
+
 	return &authModels.SignupResponse{
-		Message:    "Signup successful! You can now explore all of the exciting and amazing features",
+		Message:    "Signup successful! Please verify your email using the link we have sent to " + *user.Email,
 		StatusCode: 201,
 	}, nil
 }
@@ -215,6 +347,36 @@ func Logout(requestId string, logoutRequest authModels.LogoutRequest) (*authMode
 			zap.Any("logoutRequest", logoutRequest),
 		)
 	}
+
+	if logoutRequest.AllDevices {
+		// logging out everywhere requires proof that the caller holds a valid token of the user
+		_, validationError := tokenService.GetInstance().ValidateJwtToken(requestId, logoutRequest.AuthToken, logoutRequest.UserId)
+
+		if validationError != nil {
+			return nil, utils.GetErrorResponse("Valid auth token is required to logout from all devices", 401)
+		}
+
+		if err := tokenService.GetInstance().RevokeAllUserTokens(requestId, logoutRequest.UserId); err != nil {
+			return nil, err
+		}
+
+		return &authModels.LogoutResponse{
+			Message: "Logged out from all devices successfully",
+		}, nil
+	}
+
+	if logoutRequest.AuthToken != "" {
+		if err := tokenService.GetInstance().RevokeJwtToken(requestId, logoutRequest.AuthToken, logoutRequest.UserId); err != nil {
+			return nil, err
+		}
+	}
+
+	if logoutRequest.RefreshToken != "" {
+		if err := tokenService.GetInstance().RevokeRefreshToken(requestId, logoutRequest.RefreshToken, logoutRequest.UserId); err != nil {
+			return nil, err
+		}
+	}
+
 	return &authModels.LogoutResponse{
 		Message: "Logout successful",
 	}, nil
@@ -249,7 +411,11 @@ func ValidateToken(requestId string, validateTokenRequest authModels.ValidateTok
 }
 
 // GenerateAndSendForgotPasswordToken Function to generate forgot password token and send forgot password email back to user
-func GenerateAndSendForgotPasswordToken(requestId string, forgotPasswordRequest authModels.ForgotPasswordRequest) (*authModels.ForgotPasswordResponse, *authModels.ErrorResponse) {
+func GenerateAndSendForgotPasswordToken(
+	requestId string,
+	forgotPasswordRequest authModels.ForgotPasswordRequest,
+	clientInfo authModels.ClientInfo,
+) (*authModels.ForgotPasswordResponse, *authModels.ErrorResponse) {
 	if logger.IsDebugEnabled() {
 		logger.Debug(
 			"Processing forgot password Request",
@@ -274,37 +440,19 @@ func GenerateAndSendForgotPasswordToken(requestId string, forgotPasswordRequest
 		}
 	}
 
-	if user.LoginType == constants.UserEntityLoginTypeOauthAndOtp || user.LoginType == constants.UserEntityLoginTypeOauthOnly {
+	// accounts without a password get the same link to set their first one, their email was verified by the provider
+	if !user.LoginType.AllowsPassword() && !user.LoginType.AllowsOtp() {
 		return nil, &authModels.ErrorResponse{
 			Message:   "Invalid Request",
-			Errors:    fmt.Sprintf("You are not allowed to reset password. Please login using %s oAuth", user.OAuthProvider),
+			Errors:    fmt.Sprintf("You are not allowed to reset password. Please login using %s", describeLinkedProviders(requestId, user.Id)),
 			ErrorCode: 400,
 		}
 	}
 
-	forgotPasswordToken, err := tokenService.GetInstance().GenerateForgotPasswordToken(requestId, *user)
-
-	if err != nil {
+	if err := sendPasswordResetLink(requestId, *user, clientInfo); err != nil {
 		return nil, err
 	}
 
-	// store token in database for corresponding user
-	dbUpdated, dbUpdateError := authDao.UpdateForgotPasswordToken(requestId, user.Email, forgotPasswordToken)
-
-	if dbUpdateError != nil {
-		return nil, dbUpdateError
-	}
-
-	if !dbUpdated {
-		return nil, utils.InternalServerErrorResponse()
-	}
-
-	// now generate forgot password link which will be sent on user's email
-	tokenResetLink := utils.GenerateForgotPasswordLink(user.Email, forgotPasswordToken)
-
-	// send email to user and return success response
-	notificationService.SendForgotPasswordEmail(requestId, user.Email, user.Name, tokenResetLink)
-
 	return &authModels.ForgotPasswordResponse{
 		Success:    true,
 		Message:    "We have sent an email to " + email + " with steps to reset your password. Please follow email to continue",
@@ -313,7 +461,7 @@ func GenerateAndSendForgotPasswordToken(requestId string, forgotPasswordRequest
 }
 
 // VerifyResetPasswordToken Function to validate forgot password token and return redirect URL to reset password UI page
-func VerifyResetPasswordToken(requestId string, queryParams url.Values) (string, *authModels.ErrorResponse) {
+func VerifyResetPasswordToken(requestId string, queryParams url.Values, clientInfo authModels.ClientInfo) (string, *authModels.ErrorResponse) {
 	emailParam := queryParams["email"]
 	tokenParam := queryParams["token"]
 
@@ -321,7 +469,6 @@ func VerifyResetPasswordToken(requestId string, queryParams url.Values) (string,
 		logger.Info("Processing reset password token request",
 			zap.String(constants.RequestIdLogKey, requestId),
 			zap.Any("email", emailParam),
-			zap.Any("token", tokenParam),
 		)
 	}
 
@@ -334,47 +481,23 @@ func VerifyResetPasswordToken(requestId string, queryParams url.Values) (string,
 	email := emailParam[0]
 	token := tokenParam[0]
 
-	tokenValidationError := tokenService.GetInstance().ValidateForgotPasswordToken(requestId, token)
-
-	if tokenValidationError != nil {
-		return "", tokenValidationError
-	}
-
-	forgotPasswordTokenFromDatabase, fptfdError := authDao.GetForgotPasswordToken(requestId, email)
-
-	if fptfdError != nil {
-		return "", fptfdError
-	}
-
-	if forgotPasswordTokenFromDatabase != token {
-		if logger.IsErrorEnabled() {
-			logger.Error(
-				"Forgot Token not found in Database",
-				zap.String(constants.RequestIdLogKey, requestId),
-			)
-		}
+	user, tokenError := validateResetPasswordToken(requestId, email, token, clientInfo)
 
-		return "", &authModels.ErrorResponse{
-			Message:   "Invalid forgot password token. Please try again",
-			ErrorCode: 400,
-		}
+	if tokenError != nil {
+		return "", tokenError
 	}
 
-	redirectUrl := utils.GenerateForgotPasswordTokenRedirectUrl(email, token)
+	auditService.RecordEvent(requestId, constants.AuditEventPasswordResetVerified, user.Id, clientInfo, nil)
 
-	if logger.IsDebugEnabled() {
-		logger.Debug(
-			"Redirect URL generated is",
-			zap.String(constants.RequestIdLogKey, requestId),
-			zap.String("redirectUrl", redirectUrl),
-		)
-	}
-
-	return redirectUrl, nil
+	return utils.GenerateForgotPasswordTokenRedirectUrl(email, token), nil
 }
 
 // ResetPassword Function to actually reset password from forgot-password UI page
-func ResetPassword(requestId string, resetPasswordRequest authModels.ResetPasswordRequest) (*authModels.ResetPasswordResponse, *authModels.ErrorResponse) {
+func ResetPassword(
+	requestId string,
+	resetPasswordRequest authModels.ResetPasswordRequest,
+	clientInfo authModels.ClientInfo,
+) (*authModels.ResetPasswordResponse, *authModels.ErrorResponse) {
 	if logger.IsInfoEnabled() {
 		logger.Info(
 			"Processing Reset password Request",
@@ -402,48 +525,171 @@ func ResetPassword(requestId string, resetPasswordRequest authModels.ResetPasswo
 		}
 	}
 
-	// fetch forgot password token from DB
-	forgotPasswordTokenFromDatabase, fptfdError := authDao.GetForgotPasswordToken(requestId, email)
+	user, tokenError := validateResetPasswordToken(requestId, email, resetPasswordToken, clientInfo)
 
-	if fptfdError != nil {
-		if logger.IsErrorEnabled() {
-			logger.Error(
-				"Failed to fetch forgot password token from DB",
-				zap.String(constants.RequestIdLogKey, requestId),
-			)
-		}
-		return nil, fptfdError
+	if tokenError != nil {
+		return nil, tokenError
+	}
+
+	if policyError := passwordService.ValidatePassword(requestId, password, user.Email, user.Name); policyError != nil {
+		return nil, policyError
+	}
+
+	if reuseError := passwordService.CheckPasswordReuse(requestId, *user, password); reuseError != nil {
+		return nil, reuseError
 	}
 
-	// match provided token with DB token again for double check
-	if strings.TrimSpace(forgotPasswordTokenFromDatabase) != strings.TrimSpace(resetPasswordToken) {
+	hashedPassword, hashError := passwordService.HashPassword(password)
+
+	if hashError != nil {
 		if logger.IsErrorEnabled() {
 			logger.Error(
-				"forgot token from DB doesn't match with token provided",
+				"Error hashing password",
 				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(hashError),
 			)
 		}
+		return nil, utils.InternalServerErrorResponse()
+	}
 
-		return nil, &authModels.ErrorResponse{
-			Message:   "Invalid token provided",
-			ErrorCode: 400,
-		}
+	// the token is consumed before the password is changed so that concurrent requests can't use it twice
+	if _, consumeError := tokenService.GetInstance().ConsumeActionToken(requestId, constants.ActionTokenTypePasswordReset, resetPasswordToken); consumeError != nil {
+		auditService.RecordEvent(requestId, constants.AuditEventPasswordResetFailed, user.Id, clientInfo, map[string]string{"reason": "token_already_used"})
+		return nil, consumeError
+	}
+
+	// users setting their first password can log in using it from now on, along with their previous login methods
+	isPasswordUpdated, passwordUpdateErr := authDao.UpdatePassword(requestId, user.Id, hashedPassword, user.LoginType.WithPassword())
+
+	if passwordUpdateErr != nil {
+		return nil, passwordUpdateErr
+	}
+
+	if !isPasswordUpdated {
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	passwordService.RecordPasswordHistory(requestId, user.Id, hashedPassword)
+
+	auditService.RecordEvent(requestId, constants.AuditEventPasswordResetCompleted, user.Id, clientInfo, nil)
+
+	if user.Password == "" {
+		auditService.RecordEvent(requestId, constants.AuditEventPasswordAdded, user.Id, clientInfo, map[string]string{
+			"login_type": string(user.LoginType.WithPassword()),
+		})
+	}
+
+	notificationService.SendPasswordChangeSuccessEmail(requestId, email)
+
+	return &authModels.ResetPasswordResponse{
+		Success:    true,
+		Message:    "Password changed successfully",
+		StatusCode: 200,
+	}, nil
+}
+
+// RequestPasswordSetup Function to email the logged-in user a link to add a password to an account registered using
+// an OAuth provider. Following the link proves the user still owns the email, as for forgot password
+func RequestPasswordSetup(
+	requestId string,
+	authClaims authModels.AuthClaims,
+	clientInfo authModels.ClientInfo,
+) (*authModels.PasswordSetupResponse, *authModels.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Password Setup Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+		)
+	}
+
+	user, err := authDao.GetUserById(requestId, authClaims.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Password != "" || user.LoginType.AllowsPassword() {
+		return nil, utils.BadRequestErrorResponse("Your account already has a password, use change password to update it")
 	}
 
-	hashedPassword, bcryptError := bcrypt.GenerateFromPassword([]byte(password), 14)
+	if user.Email == "" || !user.LoginType.AllowsOtp() {
+		return nil, utils.BadRequestErrorResponse("Your account has no verified email to set a password for")
+	}
+
+	if err := sendPasswordResetLink(requestId, *user, clientInfo); err != nil {
+		return nil, err
+	}
+
+	return &authModels.PasswordSetupResponse{
+		Success:    true,
+		Message:    "We have sent an email to " + user.Email + " with steps to set your password. Please follow email to continue",
+		StatusCode: 200,
+	}, nil
+}
 
-	if bcryptError != nil {
+// ChangePassword Function to change the password of the logged-in user after verifying the current one. Every other
+// session of the user is ended so that the new password has to be used there
+func ChangePassword(
+	requestId string,
+	authClaims authModels.AuthClaims,
+	changePasswordRequest authModels.ChangePasswordRequest,
+	clientInfo authModels.ClientInfo,
+) (*authModels.ChangePasswordResponse, *authModels.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Change Password Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+		)
+	}
+
+	user, err := authDao.GetUserById(requestId, authClaims.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Password == "" {
+		return nil, utils.GetErrorResponse("Your account does not have a password", 400)
+	}
+
+	passwordValid, throttleError := throttleService.VerifyPasswordWithThrottle(requestId, *user, changePasswordRequest.CurrentPassword, clientInfo)
+
+	if throttleError != nil {
+		return nil, throttleError
+	}
+
+	if !passwordValid {
+		return nil, utils.GetErrorResponse("Current password is incorrect", 401)
+	}
+
+	if changePasswordRequest.NewPassword == changePasswordRequest.CurrentPassword {
+		return nil, utils.BadRequestErrorResponse("New password must be different from the current password")
+	}
+
+	if policyError := passwordService.ValidatePassword(requestId, changePasswordRequest.NewPassword, user.Email, user.Name); policyError != nil {
+		return nil, policyError
+	}
+
+	if reuseError := passwordService.CheckPasswordReuse(requestId, *user, changePasswordRequest.NewPassword); reuseError != nil {
+		return nil, reuseError
+	}
+
+	hashedPassword, hashError := passwordService.HashPassword(changePasswordRequest.NewPassword)
+
+	if hashError != nil {
 		if logger.IsErrorEnabled() {
 			logger.Error(
 				"Error hashing password",
 				zap.String(constants.RequestIdLogKey, requestId),
-				zap.Error(bcryptError),
+				zap.Error(hashError),
 			)
 		}
 		return nil, utils.InternalServerErrorResponse()
 	}
 
-	isPasswordUpdated, passwordUpdateErr := authDao.UpdatePassword(requestId, email, string(hashedPassword))
+	isPasswordUpdated, passwordUpdateErr := authDao.UpdatePassword(requestId, user.Id, hashedPassword, user.LoginType)
 
 	if passwordUpdateErr != nil {
 		return nil, passwordUpdateErr
@@ -453,9 +699,25 @@ func ResetPassword(requestId string, resetPasswordRequest authModels.ResetPasswo
 		return nil, utils.InternalServerErrorResponse()
 	}
 
-	notificationService.SendPasswordChangeSuccessEmail(requestId, email)
+	passwordService.RecordPasswordHistory(requestId, user.Id, hashedPassword)
 
-	return &authModels.ResetPasswordResponse{
+	if revokeError := tokenService.GetInstance().RevokeOtherUserSessions(requestId, user.Id, authClaims.SessionId); revokeError != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error revoking other sessions of user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Int16(constants.ErrorCodeLogKey, revokeError.ErrorCode),
+				zap.Any(constants.ErrorMessageLogKey, revokeError.Message),
+			)
+		}
+		return nil, revokeError
+	}
+
+	if user.Email != "" {
+		notificationService.SendPasswordChangeSuccessEmail(requestId, user.Email)
+	}
+
+	return &authModels.ChangePasswordResponse{
 		Success:    true,
 		Message:    "Password changed successfully",
 		StatusCode: 200,
@@ -485,18 +747,7 @@ func VerifyAdmin(requestId string, verifyAdminRequest authModels.VerifyAdminRequ
 		return nil, err
 	}
 
-	scopes := strings.Split(user.Scopes, ",")
-
-	var adminScopeFound = false
-
-	for _, scope := range scopes {
-		if strings.Contains(strings.ToLower(scope), "admin") {
-			adminScopeFound = true
-			break
-		}
-	}
-
-	if !adminScopeFound {
+	if !utils.HasAdminScope(user.Scopes) {
 		response := &authModels.VerifyAdminResponse{
 			Success:    false,
 			Message:    "Admin scope not found",
@@ -512,9 +763,178 @@ func VerifyAdmin(requestId string, verifyAdminRequest authModels.VerifyAdminRequ
 	}, nil
 }
 
-// function to validate provided password against the encrypted password stored in DB
-func verifyPassword(rawPassword string, encryptedPassword string) bool {
-	return bcrypt.CompareHashAndPassword([]byte(encryptedPassword), []byte(rawPassword)) == nil
+// CompleteLogin issues the MFA challenge for users with MFA enabled, otherwise the session is started right away. Every
+// login method, including the OAuth providers, must go through it once the first factor is verified
+func CompleteLogin(
+	requestId string,
+	user authModels.User,
+	loginMethod constants.SessionLoginMethod,
+	clientInfo authModels.ClientInfo,
+) (*authModels.LoginResponse, *authModels.ErrorResponse) {
+	mfaEnabled, mfaError := mfaService.IsMfaEnabled(requestId, user.Id)
+
+	if mfaError != nil {
+		return nil, mfaError
+	}
+
+	// the access token is issued by LoginWithMfa once the second factor is verified
+	if mfaEnabled {
+		mfaToken, mfaTokenError := tokenService.GetInstance().GenerateMfaChallengeToken(requestId, user.Id, loginMethod)
+
+		if mfaTokenError != nil {
+			return nil, mfaTokenError
+		}
+
+		return &authModels.LoginResponse{
+			UserId:        user.Id,
+			Name:          user.Name,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			LoginType:     string(user.LoginType),
+			MfaRequired:   true,
+			MfaToken:      mfaToken,
+		}, nil
+	}
+
+	return startLoginSession(requestId, user, loginMethod, clientInfo)
+}
+
+// function to start the session of the logged-in user and record the login time
+func startLoginSession(
+	requestId string,
+	user authModels.User,
+	loginMethod constants.SessionLoginMethod,
+	clientInfo authModels.ClientInfo,
+) (*authModels.LoginResponse, *authModels.ErrorResponse) {
+	sessionTokens, sessionError := sessionService.StartSession(requestId, user, loginMethod, clientInfo)
+
+	if sessionError != nil {
+		return nil, sessionError
+	}
+
+	authDao.UpdateTimestamp(requestId, user.Id, authDao.TimestampTypeLastLoginTime)
+
+	return &authModels.LoginResponse{
+		AccessToken:   sessionTokens.AccessToken,
+		RefreshToken:  sessionTokens.RefreshToken,
+		UserId:        user.Id,
+		Name:          user.Name,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		LoginType:     string(user.LoginType),
+	}, nil
+}
+
+// function to describe the OAuth providers linked to the user for the error messages, e.g. "google or github OAuth"
+func describeLinkedProviders(requestId string, userId string) string {
+	identities, _ := identityDao.GetUserIdentities(requestId, userId)
+
+	providers := make([]string, 0, len(identities))
+
+	for _, identity := range identities {
+		providers = append(providers, identity.Provider)
+	}
+
+	if len(providers) == 0 {
+		return "OAuth"
+	}
+
+	return strings.Join(providers, " or ") + " OAuth"
+}
+
+// function to check that the reset password token is valid, unused and issued to the user with the email, recording
+// the failed attempts
+func validateResetPasswordToken(
+	requestId string,
+	email string,
+	rawToken string,
+	clientInfo authModels.ClientInfo,
+) (*authModels.User, *authModels.ErrorResponse) {
+	invalidTokenError := utils.GetErrorResponse("Invalid forgot password token. Please try again", 400)
+
+	actionToken, err := tokenService.GetInstance().ValidateActionToken(requestId, constants.ActionTokenTypePasswordReset, strings.TrimSpace(rawToken))
+
+	if err != nil {
+		if err.ErrorCode == 400 {
+			// the failure is attributed to the user of the email rather than storing the email, so that it is cleared
+			// when the user is purged
+			var userId string
+			if user, _ := authDao.GetUserByEmail(requestId, email); user != nil {
+				userId = user.Id
+			}
+			auditService.RecordEvent(requestId, constants.AuditEventPasswordResetFailed, userId, clientInfo, map[string]string{"reason": "invalid_token"})
+			return nil, invalidTokenError
+		}
+		return nil, err
+	}
+
+	user, err := authDao.GetUserByEmail(requestId, email)
+
+	if err != nil && err.ErrorCode != 404 {
+		return nil, err
+	}
+
+	if user == nil || user.Id != actionToken.UserId {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Forgot password token was issued to a different user",
+				zap.String(constants.RequestIdLogKey, requestId),
+			)
+		}
+		auditService.RecordEvent(requestId, constants.AuditEventPasswordResetFailed, actionToken.UserId, clientInfo, map[string]string{"reason": "email_mismatch"})
+		return nil, invalidTokenError
+	}
+
+	return user, nil
+}
+
+// function to issue a password reset token and email its link to the user. Users without a password get the set
+// password email instead, the link leads to the same page. Issuing the token invalidates the previously issued ones
+func sendPasswordResetLink(requestId string, user authModels.User, clientInfo authModels.ClientInfo) *authModels.ErrorResponse {
+	validity := utils.GetEnvDurationSeconds("FORGOT_PASS_EXPIRY", 10*time.Minute)
+
+	// only the token digest is stored
+	resetPasswordToken, err := tokenService.GetInstance().IssueActionToken(requestId, user.Id, constants.ActionTokenTypePasswordReset, validity, nil)
+
+	if err != nil {
+		return err
+	}
+
+	tokenResetLink := utils.GenerateForgotPasswordLink(user.Email, resetPasswordToken)
+
+	if user.Password == "" {
+		auditService.RecordEvent(requestId, constants.AuditEventPasswordSetupRequested, user.Id, clientInfo, nil)
+		notificationService.SendSetPasswordEmail(requestId, user.Email, user.Name, tokenResetLink)
+		return nil
+	}
+
+	auditService.RecordEvent(requestId, constants.AuditEventPasswordResetRequested, user.Id, clientInfo, nil)
+	notificationService.SendForgotPasswordEmail(requestId, user.Email, user.Name, tokenResetLink)
+
+	return nil
+}
+
+// function to replace the stored password hash of the user by one using the configured algorithm and parameters.
+// Failures are only logged as the old hash keeps working
+func rehashPasswordIfNeeded(requestId string, user authModels.User, rawPassword string) {
+	if !passwordService.NeedsRehash(user.Password) {
+		return
+	}
+
+	hashedPassword, hashError := passwordService.HashPassword(rawPassword)
+
+	if hashError != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error rehashing password",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(hashError),
+			)
+		}
+		return
+	}
+
+	authDao.UpdatePasswordHash(requestId, user.Id, user.Password, hashedPassword)
 }
 
 func createUserEntity(request model.SignupRequest) *entity.User {
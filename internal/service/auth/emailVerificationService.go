@@ -0,0 +1,152 @@
+package auth_service
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	authDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/auth"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	notificationService "github.com/akgarg0472/urlshortener-auth-service/internal/service/notification"
+	tokenService "github.com/akgarg0472/urlshortener-auth-service/internal/service/token"
+	authModels "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+)
+
+// VerifyEmail Function to consume the email verification link and mark the email of its user verified. Returns the
+// frontend URL to redirect the browser to
+func VerifyEmail(requestId string, queryParams url.Values) (string, *authModels.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Verify Email Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	verificationToken := queryParams.Get("token")
+
+	if verificationToken == "" {
+		return "", utils.BadRequestErrorResponse("Token is required")
+	}
+
+	actionToken, err := tokenService.GetInstance().ConsumeActionToken(requestId, constants.ActionTokenTypeEmailVerification, verificationToken)
+
+	if err != nil {
+		return "", err
+	}
+
+	user, err := authDao.GetUserById(requestId, actionToken.UserId)
+
+	if err != nil {
+		if err.ErrorCode == 404 {
+			return "", utils.GetErrorResponse("Invalid or expired token", 400)
+		}
+		return "", err
+	}
+
+	// the link verifies the address it was sent to, which is no longer the email of the user
+	if actionToken.Payload == nil || *actionToken.Payload != user.Email {
+		if logger.IsInfoEnabled() {
+			logger.Info(
+				"Email verification token was issued for a different email",
+				zap.String(constants.RequestIdLogKey, requestId),
+			)
+		}
+		return "", utils.GetErrorResponse("Invalid or expired token", 400)
+	}
+
+	verified, err := authDao.MarkEmailVerified(requestId, user.Id)
+
+	if err != nil {
+		return "", err
+	}
+
+	if verified {
+		notificationService.SendSignupSuccessEmail(requestId, user.Email, user.Name)
+	}
+
+	return utils.GenerateEmailVerifiedRedirectUrl(), nil
+}
+
+// ResendVerificationEmail Function to send a new email verification link to the user. The response is the same
+// whether the account exists or not so that it can't be used to discover registered emails
+func ResendVerificationEmail(
+	requestId string,
+	resendRequest authModels.ResendVerificationEmailRequest,
+) (*authModels.ResendVerificationEmailResponse, *authModels.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Resend Verification Email Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("email", resendRequest.Email),
+		)
+	}
+
+	response := &authModels.ResendVerificationEmailResponse{
+		Success:    true,
+		Message:    "If " + resendRequest.Email + " is registered and not yet verified, we have sent a verification link to it",
+		StatusCode: 200,
+	}
+
+	user, err := authDao.GetUserByEmail(requestId, resendRequest.Email)
+
+	if err != nil {
+		if err.ErrorCode == 404 {
+			return response, nil
+		}
+		return nil, err
+	}
+
+	if user.EmailVerified {
+		if logger.IsInfoEnabled() {
+			logger.Info(
+				"Email is already verified",
+				zap.String(constants.RequestIdLogKey, requestId),
+			)
+		}
+		return response, nil
+	}
+
+	if err := sendEmailVerificationLink(requestId, *user); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// function to issue the email verification token of the user and email the link, invalidating the previous links
+func sendEmailVerificationLink(requestId string, user authModels.User) *authModels.ErrorResponse {
+	validity := utils.GetEnvDurationSeconds("EMAIL_VERIFICATION_EXPIRY", 24*time.Hour)
+
+	// the email is kept with the token so that the link can't verify an email the user has changed to since
+	email := user.Email
+
+	verificationToken, err := tokenService.GetInstance().IssueActionToken(requestId, user.Id, constants.ActionTokenTypeEmailVerification, validity, &email)
+
+	if err != nil {
+		return err
+	}
+
+	notificationService.SendEmailVerificationEmail(requestId, user.Email, user.Name, utils.GenerateEmailVerificationLink(verificationToken), int64(validity.Hours()))
+
+	return nil
+}
+
+// function to mark the email of the user verified once they log in using a code or link sent to it
+func markEmailVerifiedOnLogin(requestId string, user *authModels.User) {
+	if user.EmailVerified {
+		return
+	}
+
+	if verified, _ := authDao.MarkEmailVerified(requestId, user.Id); verified {
+		user.EmailVerified = true
+	}
+}
+
+// function to check if the users with unverified email are allowed to log in using their password
+func isUnverifiedEmailLoginAllowed() bool {
+	allowed, err := strconv.ParseBool(utils.GetEnvVariable("ALLOW_UNVERIFIED_EMAIL_LOGIN", "true"))
+	return err != nil || allowed
+}
@@ -0,0 +1,128 @@
+package auth_service
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	authDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/auth"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	notificationService "github.com/akgarg0472/urlshortener-auth-service/internal/service/notification"
+	tokenService "github.com/akgarg0472/urlshortener-auth-service/internal/service/token"
+	authModels "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+)
+
+// RequestMagicLink Function to email a single-use login link to the user. The response is the same whether the
+// account exists or not so that it can't be used to discover registered emails
+func RequestMagicLink(requestId string, magicLinkRequest authModels.MagicLinkRequest) (*authModels.MagicLinkResponse, *authModels.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Magic Link Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("email", magicLinkRequest.Email),
+		)
+	}
+
+	response := &authModels.MagicLinkResponse{
+		Success:    true,
+		Message:    "If an account exists for " + magicLinkRequest.Email + ", we have sent a login link to it",
+		StatusCode: 200,
+	}
+
+	user, err := authDao.GetUserByEmail(requestId, magicLinkRequest.Email)
+
+	if err != nil {
+		if err.ErrorCode == 404 {
+			return response, nil
+		}
+		return nil, err
+	}
+
+	if !user.LoginType.AllowsMagicLink() {
+		if logger.IsInfoEnabled() {
+			logger.Info(
+				"User is not allowed to log in using magic link",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("loginType", string(user.LoginType)),
+			)
+		}
+		return response, nil
+	}
+
+	validity := utils.GetEnvDurationSeconds("MAGIC_LINK_EXPIRY", 15*time.Minute)
+
+	magicLinkToken, tokenError := tokenService.GetInstance().IssueActionToken(requestId, user.Id, constants.ActionTokenTypeMagicLink, validity, nil)
+
+	if tokenError != nil {
+		return nil, tokenError
+	}
+
+	notificationService.SendMagicLinkEmail(requestId, user.Email, user.Name, utils.GenerateMagicLink(magicLinkToken), int64(validity.Minutes()))
+
+	return response, nil
+}
+
+// VerifyMagicLink Function to consume the magic link and log in its user. Returns the login response along with the
+// frontend URL to redirect the browser to
+func VerifyMagicLink(
+	requestId string,
+	queryParams url.Values,
+	clientInfo authModels.ClientInfo,
+) (*authModels.LoginResponse, string, *authModels.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Verify Magic Link Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	magicLinkToken := queryParams.Get("token")
+
+	if magicLinkToken == "" {
+		return nil, "", utils.BadRequestErrorResponse("Token is required")
+	}
+
+	actionToken, err := tokenService.GetInstance().ConsumeActionToken(requestId, constants.ActionTokenTypeMagicLink, magicLinkToken)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	user, err := authDao.GetUserById(requestId, actionToken.UserId)
+
+	if err != nil {
+		if err.ErrorCode == 404 {
+			return nil, "", utils.GetErrorResponse("Invalid or expired token", 400)
+		}
+		return nil, "", err
+	}
+
+	if !user.LoginType.AllowsMagicLink() {
+		return nil, "", utils.GetErrorResponse("Invalid or expired token", 400)
+	}
+
+	markEmailVerifiedOnLogin(requestId, user)
+
+	loginResponse, err := CompleteLogin(requestId, *user, constants.SessionLoginMethodMagicLink, clientInfo)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if loginResponse.MfaRequired {
+		return loginResponse, utils.GenerateMagicLinkRedirectUrl(url.Values{"mfa_token": {loginResponse.MfaToken}}), nil
+	}
+
+	// by default the frontend relies on the auth_token cookie, otherwise the tokens are handed over in the fragment
+	if utils.GetEnvVariable("MAGIC_LINK_REDIRECT_MODE", "cookie") == "token" {
+		return loginResponse, utils.GenerateMagicLinkRedirectUrl(url.Values{
+			"auth_token":    {loginResponse.AccessToken},
+			"refresh_token": {loginResponse.RefreshToken},
+			"user_id":       {loginResponse.UserId},
+		}), nil
+	}
+
+	return loginResponse, utils.GenerateDashboardUrl(), nil
+}
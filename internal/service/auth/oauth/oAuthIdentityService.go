@@ -0,0 +1,253 @@
+package oauth_service
+
+import (
+	"strings"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	authDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/auth"
+	identityDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/identity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	auditService "github.com/akgarg0472/urlshortener-auth-service/internal/service/audit"
+	throttleService "github.com/akgarg0472/urlshortener-auth-service/internal/service/throttle"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// StartIdentityLink Function to start linking an identity of the provider to the authenticated user. Users having a
+// password must prove they own the account by providing it, the others by completing an authorization request with one
+// of their linked providers. Returns the provider URL to send the browser to
+func StartIdentityLink(
+	requestId string,
+	authClaims model.AuthClaims,
+	provider string,
+	linkRequest model.LinkOAuthIdentityRequest,
+	clientInfo model.ClientInfo,
+) (*model.OAuthAuthorizeResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Start Identity Link Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+			zap.String("provider", provider),
+		)
+	}
+
+	user, err := authDao.GetUserById(requestId, authClaims.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Password == "" {
+		if linkRequest.OAuth == nil {
+			return nil, utils.BadRequestErrorResponse("Log in again with one of your linked accounts to link an account")
+		}
+
+		if reauthError := VerifyReauthentication(requestId, authClaims, *linkRequest.OAuth); reauthError != nil {
+			return nil, reauthError
+		}
+	} else {
+		if linkRequest.Password == "" {
+			return nil, utils.BadRequestErrorResponse("Password is required to link an account")
+		}
+
+		passwordValid, throttleError := throttleService.VerifyPasswordWithThrottle(requestId, *user, linkRequest.Password, clientInfo)
+
+		if throttleError != nil {
+			return nil, throttleError
+		}
+
+		if !passwordValid {
+			return nil, utils.GetErrorResponse("Password is incorrect", 401)
+		}
+	}
+
+	return startAuthorization(requestId, provider, constants.OAuthStatePurposeLink, &user.Id)
+}
+
+// LinkIdentity Function to complete the link started by StartIdentityLink. The state must have been issued to the
+// authenticated user, and the identity must not be linked to any user yet
+func LinkIdentity(
+	requestId string,
+	authClaims model.AuthClaims,
+	callbackRequest model.OAuthCallbackRequest,
+	clientInfo model.ClientInfo,
+) (*model.LinkOAuthIdentityResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Link Identity Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+			zap.String("provider", string(callbackRequest.Provider)),
+		)
+	}
+
+	oAuthState, err := consumeOAuthState(requestId, callbackRequest.State, callbackRequest.Provider, constants.OAuthStatePurposeLink, authClaims.UserId, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	profileInfo, _, err := fetchProfileInfo(requestId, callbackRequest, oAuthState)
+
+	if err != nil {
+		return nil, err
+	}
+
+	existingIdentity, err := identityDao.GetUserIdentity(requestId, profileInfo.OAuthProvider, profileInfo.OAuthId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if existingIdentity != nil {
+		if existingIdentity.UserId == authClaims.UserId {
+			return nil, utils.GetErrorResponse("This "+profileInfo.OAuthProvider+" account is already linked to your account", 409)
+		}
+		return nil, utils.GetErrorResponse("This "+profileInfo.OAuthProvider+" account is linked to another account", 409)
+	}
+
+	identities, err := identityDao.GetUserIdentities(requestId, authClaims.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, identity := range identities {
+		if identity.Provider == profileInfo.OAuthProvider {
+			return nil, utils.GetErrorResponse("A "+profileInfo.OAuthProvider+" account is already linked to your account, unlink it first", 409)
+		}
+	}
+
+	identity := &entity.UserIdentity{
+		Id:             strings.ReplaceAll(uuid.New().String(), "-", ""),
+		UserId:         authClaims.UserId,
+		Provider:       profileInfo.OAuthProvider,
+		ProviderUserId: profileInfo.OAuthId,
+	}
+
+	if profileInfo.Email != "" {
+		identity.Email = &profileInfo.Email
+	}
+
+	if err := identityDao.SaveUserIdentity(requestId, identity); err != nil {
+		return nil, err
+	}
+
+	auditService.RecordEvent(requestId, constants.AuditEventIdentityLinked, authClaims.UserId, clientInfo, map[string]string{
+		"provider": identity.Provider,
+	})
+
+	return &model.LinkOAuthIdentityResponse{
+		Success:    true,
+		Message:    "Your " + identity.Provider + " account is linked",
+		Identity:   mapIdentityToModel(*identity),
+		StatusCode: 200,
+	}, nil
+}
+
+// GetIdentities Function to list the provider identities linked to the authenticated user
+func GetIdentities(requestId string, authClaims model.AuthClaims) (*model.OAuthIdentitiesResponse, *model.ErrorResponse) {
+	identities, err := identityDao.GetUserIdentities(requestId, authClaims.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.OAuthIdentitiesResponse{
+		Success:    true,
+		Identities: make([]model.OAuthIdentity, 0, len(identities)),
+		StatusCode: 200,
+	}
+
+	for _, identity := range identities {
+		response.Identities = append(response.Identities, *mapIdentityToModel(identity))
+	}
+
+	return response, nil
+}
+
+// UnlinkIdentity Function to unlink the provider identity from the authenticated user. The last identity can only be
+// unlinked if the user can still log in using a password or an email code or link
+func UnlinkIdentity(
+	requestId string,
+	authClaims model.AuthClaims,
+	identityId string,
+	clientInfo model.ClientInfo,
+) (*model.UnlinkOAuthIdentityResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Unlink Identity Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+			zap.String("identityId", identityId),
+		)
+	}
+
+	user, err := authDao.GetUserById(requestId, authClaims.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := identityDao.GetUserIdentities(requestId, user.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var identityToUnlink *entity.UserIdentity
+
+	for i := range identities {
+		if identities[i].Id == identityId {
+			identityToUnlink = &identities[i]
+			break
+		}
+	}
+
+	if identityToUnlink == nil {
+		return nil, utils.GetErrorResponse("Identity not found", 404)
+	}
+
+	deleted, err := identityDao.DeleteUserIdentity(requestId, user.Id, identityId, !hasLoginMethodWithoutIdentity(*user))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !deleted {
+		return nil, utils.BadRequestErrorResponse("You can't unlink the last login method of your account")
+	}
+
+	auditService.RecordEvent(requestId, constants.AuditEventIdentityUnlinked, user.Id, clientInfo, map[string]string{
+		"provider": identityToUnlink.Provider,
+	})
+
+	return &model.UnlinkOAuthIdentityResponse{
+		Success:    true,
+		Message:    "Your " + identityToUnlink.Provider + " account is unlinked",
+		StatusCode: 200,
+	}, nil
+}
+
+// function to check if the user can log in without any provider identity, using a password or an email code or link
+func hasLoginMethodWithoutIdentity(user model.User) bool {
+	if user.Password != "" && user.LoginType.AllowsPassword() {
+		return true
+	}
+
+	return user.Email != "" && user.LoginType.AllowsMagicLink()
+}
+
+func mapIdentityToModel(identity entity.UserIdentity) *model.OAuthIdentity {
+	return &model.OAuthIdentity{
+		Id:          identity.Id,
+		Provider:    identity.Provider,
+		Email:       identity.Email,
+		CreatedAt:   identity.CreatedAt,
+		LastLoginAt: identity.LastLoginAt,
+	}
+}
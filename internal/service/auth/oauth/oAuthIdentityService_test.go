@@ -0,0 +1,45 @@
+package oauth_service
+
+import (
+	"testing"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+)
+
+func TestHasLoginMethodWithoutIdentity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		user     model.User
+		expected bool
+	}{
+		{
+			name:     "password",
+			user:     model.User{Password: "hash", LoginType: constants.UserEntityLoginTypeOauthAndPassword},
+			expected: true,
+		},
+		{
+			name:     "emailed code",
+			user:     model.User{Email: "user@example.com", LoginType: constants.UserEntityLoginTypeOauthAndOtp},
+			expected: true,
+		},
+		{
+			name:     "no email for the emailed code",
+			user:     model.User{LoginType: constants.UserEntityLoginTypeOauthAndOtp},
+			expected: false,
+		},
+		{
+			name:     "provider only",
+			user:     model.User{Email: "user@example.com", LoginType: constants.UserEntityLoginTypeOauthOnly},
+			expected: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if actual := hasLoginMethodWithoutIdentity(testCase.user); actual != testCase.expected {
+				t.Errorf("hasLoginMethodWithoutIdentity returned %v, want %v", actual, testCase.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+package oauth_service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	enums "github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+)
+
+var registry = NewProviderRegistry()
+
+// OAuthTokens are the tokens issued by the provider in exchange of the authorization code
+type OAuthTokens struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IdToken     string `json:"id_token"`
+}
+
+// AuthUrlParams are the parameters of the authorization request generated by the service
+type AuthUrlParams struct {
+	State         string
+	CodeChallenge string
+	Nonce         string
+}
+
+// OAuthProvider is an identity provider users can log in with
+type OAuthProvider interface {
+	// Name returns the name of the provider, e.g. google
+	Name() string
+	// Info returns the public configuration of the provider listed by /oauth/providers
+	Info() model.OAuthProvider
+	// LoginMethod returns the login method of the sessions started using the provider
+	LoginMethod() enums.SessionLoginMethod
+	// BuildAuthUrl returns the URL of the provider the browser is sent to for the authorization request
+	BuildAuthUrl(requestId string, params AuthUrlParams) (string, *model.ErrorResponse)
+	// ExchangeCode exchanges the authorization code for the tokens of the user
+	ExchangeCode(requestId string, code string, codeVerifier string) (*OAuthTokens, *model.ErrorResponse)
+	// FetchProfile returns the profile of the user the tokens were issued to. Providers issuing an ID token check it
+	// against the nonce of the authorization request
+	FetchProfile(requestId string, tokens *OAuthTokens, nonce string) (*ProfileInfo, *model.ErrorResponse)
+}
+
+// ProviderRegistry holds the OAuth providers users can log in with, by name
+type ProviderRegistry struct {
+	mutex     sync.RWMutex
+	providers map[string]OAuthProvider
+}
+
+func NewProviderRegistry() *ProviderRegistry {
+	return &ProviderRegistry{
+		providers: make(map[string]OAuthProvider),
+	}
+}
+
+// GetRegistry returns the registry populated by InitOAuthProviders
+func GetRegistry() *ProviderRegistry {
+	return registry
+}
+
+// Register adds the provider, replacing the provider previously registered with the same name
+func (r *ProviderRegistry) Register(provider OAuthProvider) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.providers[provider.Name()] = provider
+}
+
+// Get returns the provider with the name
+func (r *ProviderRegistry) Get(name string) (OAuthProvider, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	provider, exists := r.providers[name]
+	return provider, exists
+}
+
+// List returns the public configuration of the providers with the names, or of every provider if no name is given
+func (r *ProviderRegistry) List(names []string) []model.OAuthProvider {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var providers []model.OAuthProvider
+
+	if len(names) == 0 {
+		for _, provider := range r.providers {
+			providers = append(providers, provider.Info())
+		}
+
+		sort.Slice(providers, func(i, j int) bool {
+			return providers[i].Provider < providers[j].Provider
+		})
+
+		return providers
+	}
+
+	for _, name := range names {
+		if provider, exists := r.providers[name]; exists {
+			providers = append(providers, provider.Info())
+		}
+	}
+
+	return providers
+}
+
+// NewProvider creates the provider configured by the row of the oauth_providers table
+func NewProvider(config entity.OAuthProvider, httpClient *http.Client) (OAuthProvider, error) {
+	if config.Type == enums.OAuthProviderTypeOidc {
+		return NewOidcProvider(config, httpClient)
+	}
+
+	switch enums.OAuthProvider(config.Provider) {
+	case enums.OauthProviderGoogle:
+		return NewGoogleProvider(config, httpClient), nil
+	case enums.OauthProviderGithub:
+		return NewGitHubProvider(config, httpClient), nil
+	}
+
+	return nil, fmt.Errorf("unsupported OAuth provider %s, set its type to oidc if it supports OpenID Connect", config.Provider)
+}
+
+// providerConfig is the configuration shared by every type of provider
+type providerConfig struct {
+	name         string
+	providerType enums.OAuthProviderType
+	clientId     string
+	clientSecret string
+	redirectUri  string
+	authUrl      string
+	accessType   string
+	scope        string
+}
+
+// function to create the configuration of the provider. The client id, secret and redirect URI can be overridden by
+// the OAUTH_<PROVIDER>_CLIENT_* env variables, where PROVIDER is the upper-cased name with other characters than
+// letters and digits replaced by underscores
+func newProviderConfig(config entity.OAuthProvider) providerConfig {
+	envPrefix := "OAUTH_" + strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(config.Provider))
+
+	providerType := config.Type
+
+	if providerType == "" {
+		providerType = enums.OAuthProviderTypeOAuth2
+	}
+
+	return providerConfig{
+		name:         config.Provider,
+		providerType: providerType,
+		clientId:     utils.GetEnvVariable(envPrefix+"_CLIENT_ID", config.ClientID),
+		clientSecret: utils.GetEnvVariable(envPrefix+"_CLIENT_SECRET", utils.GetStringOrNil(config.ClientSecret)),
+		redirectUri:  utils.GetEnvVariable(envPrefix+"_CLIENT_REDIRECT_URI", config.RedirectURI),
+		authUrl:      config.BaseUrl,
+		accessType:   config.AccessType,
+		scope:        config.Scope,
+	}
+}
+
+func (c *providerConfig) Name() string {
+	return c.name
+}
+
+func (c *providerConfig) Info() model.OAuthProvider {
+	return model.OAuthProvider{
+		Provider:    c.name,
+		Type:        string(c.providerType),
+		ClientId:    c.clientId,
+		BaseUrl:     c.authUrl,
+		RedirectURI: c.redirectUri,
+		AccessType:  c.accessType,
+		Scope:       c.scope,
+	}
+}
+
+// function to build the authorization URL on the endpoint using the PKCE S256 method. The nonce is only sent when
+// includeNonce is set, as plain OAuth providers don't support it
+func (c *providerConfig) buildAuthUrl(
+	requestId string,
+	endpoint string,
+	params AuthUrlParams,
+	includeNonce bool,
+) (string, *model.ErrorResponse) {
+	authorizationUrl, err := url.Parse(endpoint)
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Invalid OAuth provider authorization URL",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", c.name),
+				zap.Error(err),
+			)
+		}
+		return "", utils.InternalServerErrorResponse()
+	}
+
+	queryParams := authorizationUrl.Query()
+	queryParams.Set("client_id", c.clientId)
+	queryParams.Set("redirect_uri", c.redirectUri)
+	queryParams.Set("response_type", "code")
+	queryParams.Set("state", params.State)
+	queryParams.Set("code_challenge", params.CodeChallenge)
+	queryParams.Set("code_challenge_method", "S256")
+
+	if c.scope != "" {
+		queryParams.Set("scope", c.scope)
+	}
+
+	if c.accessType != "" {
+		queryParams.Set("access_type", c.accessType)
+	}
+
+	if includeNonce {
+		queryParams.Set("nonce", params.Nonce)
+	}
+
+	authorizationUrl.RawQuery = queryParams.Encode()
+
+	return authorizationUrl.String(), nil
+}
+
+// function to exchange the authorization code using a JSON request to the token endpoint
+func (c *providerConfig) exchangeCode(
+	requestId string,
+	httpClient *http.Client,
+	tokenUrl string,
+	requestBody map[string]string,
+) (*OAuthTokens, *model.ErrorResponse) {
+	body, err := json.Marshal(requestBody)
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error creating JSON request",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	req, err := http.NewRequest(http.MethodPost, tokenUrl, bytes.NewBuffer(body))
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Failed to create access token request",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Failed to get access token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", c.name),
+				zap.Error(err),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		var response map[string]any
+		_ = json.NewDecoder(resp.Body).Decode(&response)
+
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Non 2xx status code received from access token request",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", c.name),
+				zap.Int(constants.StatusCodeLogKey, resp.StatusCode),
+				zap.Any("response_body", response),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	var tokens OAuthTokens
+
+	// GitHub reports errors like an invalid code with a 200 status and no token
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil || tokens.AccessToken == "" {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Failed to decode access token response",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", c.name),
+				zap.Error(err),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return &tokens, nil
+}
+
+// function to fetch the user info of the tokens from the endpoint into target
+func (c *providerConfig) fetchUserInfo(
+	requestId string,
+	httpClient *http.Client,
+	userInfoUrl string,
+	tokens *OAuthTokens,
+	target any,
+) *model.ErrorResponse {
+	req, err := http.NewRequest(http.MethodGet, userInfoUrl, nil)
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Failed to create user info request",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s %s", tokens.TokenType, tokens.AccessToken))
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Failed to get user info",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", c.name),
+				zap.Error(err),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Invalid status code received from user info request",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", c.name),
+				zap.Int(constants.StatusCodeLogKey, resp.StatusCode),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Failed to decode user info response",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", c.name),
+				zap.Error(err),
+			)
+		}
+		return utils.InternalServerErrorResponse()
+	}
+
+	return nil
+}
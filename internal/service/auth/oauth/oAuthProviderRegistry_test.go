@@ -0,0 +1,111 @@
+package oauth_service
+
+import (
+	"net/http"
+	"testing"
+
+	enums "github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+)
+
+func newTestRegistryProvider(t *testing.T, name string, providerType enums.OAuthProviderType) OAuthProvider {
+	t.Helper()
+
+	issuer := "https://" + name + ".example.com"
+
+	provider, err := NewProvider(entity.OAuthProvider{
+		Provider: name,
+		Type:     providerType,
+		ClientID: name + "-client",
+		BaseUrl:  "https://" + name + ".example.com/authorize",
+		Issuer:   &issuer,
+	}, http.DefaultClient)
+
+	if err != nil {
+		t.Fatalf("creating provider %s: %v", name, err)
+	}
+
+	return provider
+}
+
+func TestProviderRegistryRegisterAndGet(t *testing.T) {
+	registry := NewProviderRegistry()
+	google := newTestRegistryProvider(t, "google", enums.OAuthProviderTypeOAuth2)
+
+	registry.Register(google)
+
+	provider, exists := registry.Get("google")
+
+	if !exists || provider != google {
+		t.Fatalf("Get returned %v, %v, want the registered provider", provider, exists)
+	}
+
+	if _, exists := registry.Get("github"); exists {
+		t.Error("Get found a provider which was not registered")
+	}
+
+	replacement := newTestRegistryProvider(t, "google", enums.OAuthProviderTypeOAuth2)
+	registry.Register(replacement)
+
+	if provider, _ := registry.Get("google"); provider != replacement {
+		t.Error("Register did not replace the provider registered with the same name")
+	}
+}
+
+func TestProviderRegistryList(t *testing.T) {
+	registry := NewProviderRegistry()
+	registry.Register(newTestRegistryProvider(t, "keycloak", enums.OAuthProviderTypeOidc))
+	registry.Register(newTestRegistryProvider(t, "github", enums.OAuthProviderTypeOAuth2))
+	registry.Register(newTestRegistryProvider(t, "google", enums.OAuthProviderTypeOAuth2))
+
+	testCases := []struct {
+		name     string
+		names    []string
+		expected []string
+	}{
+		{name: "all providers sorted by name", names: nil, expected: []string{"github", "google", "keycloak"}},
+		{name: "requested providers in order", names: []string{"keycloak", "google"}, expected: []string{"keycloak", "google"}},
+		{name: "unknown providers skipped", names: []string{"unknown", "github"}, expected: []string{"github"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			providers := registry.List(testCase.names)
+
+			if len(providers) != len(testCase.expected) {
+				t.Fatalf("List returned %d providers, want %d", len(providers), len(testCase.expected))
+			}
+
+			for i, provider := range providers {
+				if provider.Provider != testCase.expected[i] {
+					t.Errorf("List returned %q at %d, want %q", provider.Provider, i, testCase.expected[i])
+				}
+			}
+		})
+	}
+
+	if providers := registry.List(nil); providers[2].Type != string(enums.OAuthProviderTypeOidc) {
+		t.Errorf("List returned type %q for the OIDC provider", providers[2].Type)
+	}
+}
+
+func TestNewProviderRejectsUnsupportedProvider(t *testing.T) {
+	if _, err := NewProvider(entity.OAuthProvider{Provider: "unknown"}, http.DefaultClient); err == nil {
+		t.Error("NewProvider accepted an OAuth2 provider without integration")
+	}
+}
+
+func TestNewProviderAppliesEnvOverrides(t *testing.T) {
+	t.Setenv("OAUTH_MY_KEYCLOAK_CLIENT_ID", "env-client")
+	t.Setenv("OAUTH_MY_KEYCLOAK_CLIENT_REDIRECT_URI", "http://env/callback")
+
+	info := newTestRegistryProvider(t, "my-keycloak", enums.OAuthProviderTypeOidc).Info()
+
+	if info.ClientId != "env-client" || info.RedirectURI != "http://env/callback" {
+		t.Errorf("OIDC provider ignored the env overrides: %+v", info)
+	}
+
+	if info.Type != string(enums.OAuthProviderTypeOidc) {
+		t.Errorf("OIDC provider has type %q", info.Type)
+	}
+}
@@ -0,0 +1,81 @@
+package oauth_service
+
+import (
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	authDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/auth"
+	identityDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/identity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+)
+
+// StartReauthentication Function to start the authorization request the authenticated user completes to prove again
+// it owns the account before a sensitive operation. Users having a password must confirm using it instead, so that
+// the request can't be used to link an identity without it
+func StartReauthentication(
+	requestId string,
+	authClaims model.AuthClaims,
+	provider string,
+) (*model.OAuthAuthorizeResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Start Reauthentication Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+			zap.String("provider", provider),
+		)
+	}
+
+	user, err := authDao.GetUserById(requestId, authClaims.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Password != "" {
+		return nil, utils.BadRequestErrorResponse("Your account has a password, confirm using it instead")
+	}
+
+	return startAuthorization(requestId, provider, constants.OAuthStatePurposeReauthentication, &user.Id)
+}
+
+// VerifyReauthentication Function to complete the authorization request started by StartReauthentication. The
+// provider must return an identity linked to the authenticated user
+func VerifyReauthentication(
+	requestId string,
+	authClaims model.AuthClaims,
+	callbackRequest model.OAuthCallbackRequest,
+) *model.ErrorResponse {
+	oAuthState, err := consumeOAuthState(requestId, callbackRequest.State, callbackRequest.Provider, constants.OAuthStatePurposeReauthentication, authClaims.UserId, "")
+
+	if err != nil {
+		return err
+	}
+
+	profileInfo, _, err := fetchProfileInfo(requestId, callbackRequest, oAuthState)
+
+	if err != nil {
+		return err
+	}
+
+	identity, err := identityDao.GetUserIdentity(requestId, profileInfo.OAuthProvider, profileInfo.OAuthId)
+
+	if err != nil {
+		return err
+	}
+
+	if identity == nil || identity.UserId != authClaims.UserId {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Reauthentication identity is not linked to user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("userId", authClaims.UserId),
+				zap.String("provider", profileInfo.OAuthProvider),
+			)
+		}
+		return utils.GetErrorResponse("This "+profileInfo.OAuthProvider+" account is not linked to your account", 401)
+	}
+
+	return nil
+}
@@ -2,7 +2,9 @@ package oauth_service
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/akgarg0472/urlshortener-auth-service/constants"
 	enums "github.com/akgarg0472/urlshortener-auth-service/constants"
@@ -11,19 +13,16 @@ import (
 	"go.uber.org/zap"
 
 	authDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/auth"
+	identityDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/identity"
 	oauthDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/oauth"
+	authService "github.com/akgarg0472/urlshortener-auth-service/internal/service/auth"
 	kafka_service "github.com/akgarg0472/urlshortener-auth-service/internal/service/kafka"
 	notificationService "github.com/akgarg0472/urlshortener-auth-service/internal/service/notification"
-	tokenService "github.com/akgarg0472/urlshortener-auth-service/internal/service/token"
 	"github.com/akgarg0472/urlshortener-auth-service/model"
 	"github.com/akgarg0472/urlshortener-auth-service/utils"
 	"github.com/google/uuid"
 )
 
-var (
-	oAuthProvidersMapping map[string]model.OAuthProvider
-)
-
 type ProfileInfo struct {
 	OAuthId        string
 	Name           string
@@ -36,65 +35,52 @@ func (p ProfileInfo) String() string {
 	return fmt.Sprintf(`{"OAuthId":"%s", "Name":"%s", "ProfilePicture":"%s", "Email":"%s"}`, p.OAuthId, p.Name, p.ProfilePicture, p.Email)
 }
 
-type AccessTokenResponse struct {
-	AccessToken string
-	TokenType   string
-}
-
 func InitOAuthProviders() {
 	logger.Info("Initializing oAuth providers")
 	clients := oauthDao.FetchOAuthProviders()
 
-	if oAuthProvidersMapping == nil {
-		oAuthProvidersMapping = make(map[string]model.OAuthProvider)
+	httpClient := &http.Client{
+		Timeout: utils.GetEnvDurationSeconds("OAUTH_HTTP_TIMEOUT", 10*time.Second),
 	}
 
-	for _, _client := range clients {
-		client := model.OAuthProvider{
-			Provider:    _client.Provider,
-			ClientId:    _client.ClientID,
-			BaseUrl:     _client.BaseUrl,
-			RedirectURI: _client.RedirectURI,
-			AccessType:  _client.AccessType,
-			Scope:       _client.Scope,
+	for _, client := range clients {
+		provider, err := NewProvider(client, httpClient)
+
+		if err != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error(
+					"Skipping invalid OAuth provider",
+					zap.String("provider", client.Provider),
+					zap.Error(err),
+				)
+			}
+			continue
 		}
 
-		oAuthProvidersMapping[client.Provider] = client
+		registry.Register(provider)
 	}
 
 	if logger.IsDebugEnabled() {
 		logger.Debug(
 			"Loaded OAuth providers",
-			zap.Any("oAuthProviders", oAuthProvidersMapping),
+			zap.Any("oAuthProviders", registry.List(nil)),
 		)
 	}
 }
 
 func GetOAuthProvider(query string) []model.OAuthProvider {
-	var clients []model.OAuthProvider
-
 	if query == "" {
-		for _, client := range oAuthProvidersMapping {
-			clients = append(clients, client)
-		}
-
-		return clients
-	}
-
-	providers := strings.Split(query, ",")
-
-	for _, provider := range providers {
-		if client, exists := oAuthProvidersMapping[provider]; exists {
-			clients = append(clients, client)
-		}
+		return registry.List(nil)
 	}
 
-	return clients
+	return registry.List(strings.Split(query, ","))
 }
 
 func ProcessCallbackRequest(
 	requestId string,
 	oAuthCallbackRequest model.OAuthCallbackRequest,
+	browserBinding string,
+	clientInfo model.ClientInfo,
 ) (*model.OAuthCallbackResponse, *model.ErrorResponse) {
 	if logger.IsInfoEnabled() {
 		logger.Info(
@@ -103,8 +89,14 @@ func ProcessCallbackRequest(
 		)
 	}
 
+	oAuthState, err := consumeOAuthState(requestId, oAuthCallbackRequest.State, oAuthCallbackRequest.Provider, enums.OAuthStatePurposeLogin, "", browserBinding)
+
+	if err != nil {
+		return nil, err
+	}
+
 	var newUser bool
-	profileInfo, err := getProfileInfo(requestId, oAuthCallbackRequest)
+	profileInfo, provider, err := fetchProfileInfo(requestId, oAuthCallbackRequest, oAuthState)
 
 	if err != nil {
 		if logger.IsErrorEnabled() {
@@ -119,7 +111,7 @@ func ProcessCallbackRequest(
 	}
 
 	// checks if user is registered or not
-	user, err := getExistingUser(requestId, *profileInfo)
+	user, identity, err := getExistingUser(requestId, *profileInfo)
 
 	if user != nil {
 		if logger.IsInfoEnabled() {
@@ -129,6 +121,7 @@ func ProcessCallbackRequest(
 			)
 		}
 		newUser = false
+		identityDao.UpdateIdentityLastLoginTime(requestId, identity.Id)
 	} else if err.ErrorCode == 404 {
 		if logger.IsInfoEnabled() {
 			logger.Info(
@@ -181,22 +174,13 @@ func ProcessCallbackRequest(
 		return nil, err
 	}
 
-	jwtToken, jwtError := tokenService.GetInstance().GenerateJwtToken(requestId, *user)
+	// accounts linked to a provider may have MFA enabled, the provider only proves the first factor
+	loginResponse, loginError := authService.CompleteLogin(requestId, *user, provider.LoginMethod(), clientInfo)
 
-	if jwtError != nil {
-		if logger.IsErrorEnabled() {
-			logger.Error(
-				"Error generating auth token",
-				zap.String(constants.RequestIdLogKey, requestId),
-				zap.Int16(constants.ErrorCodeLogKey, jwtError.ErrorCode),
-				zap.Any(constants.ErrorMessageLogKey, jwtError.Message),
-			)
-		}
-		return nil, jwtError
+	if loginError != nil {
+		return nil, loginError
 	}
 
-	authDao.UpdateTimestamp(requestId, user.Id, authDao.TimestampTypeLastLoginTime)
-
 	message := ""
 	if newUser {
 		message = "Welcome onboard: " + profileInfo.Name
@@ -205,46 +189,60 @@ func ProcessCallbackRequest(
 	}
 
 	return &model.OAuthCallbackResponse{
-		AuthToken: jwtToken,
-		UserId:    user.Id,
-		Name:      user.Name,
-		Email:     user.Email,
-		Success:   true,
-		IsNewUser: newUser,
-		Message:   message,
-		LoginType: string(user.LoginType),
+		AuthToken:    loginResponse.AccessToken,
+		RefreshToken: loginResponse.RefreshToken,
+		UserId:       user.Id,
+		Name:         user.Name,
+		Email:        user.Email,
+		Success:      true,
+		IsNewUser:    newUser,
+		Message:      message,
+		LoginType:    string(user.LoginType),
+		MfaRequired:  loginResponse.MfaRequired,
+		MfaToken:     loginResponse.MfaToken,
 	}, nil
 }
 
 func registerUser(requestId string, profileInfo ProfileInfo) (*model.User, *model.ErrorResponse) {
 	userToSave := createUserEntity(profileInfo)
 
-	registeredUser, err := authDao.SaveUser(requestId, userToSave)
+	identity := &entity2.UserIdentity{
+		Id:             strings.ReplaceAll(uuid.New().String(), "-", ""),
+		Provider:       profileInfo.OAuthProvider,
+		ProviderUserId: profileInfo.OAuthId,
+		Email:          userToSave.Email,
+	}
+
+	registeredUser, err := authDao.SaveUserWithIdentity(requestId, userToSave, identity)
 
 	if err != nil {
 		return nil, err
 	}
 
 	return &model.User{
-		Id:                  registeredUser.Id,
-		Name:                registeredUser.Name,
-		Email:               utils.GetStringOrNil(registeredUser.Email),
-		Scopes:              registeredUser.Scopes,
-		ForgotPasswordToken: utils.GetStringOrNil(registeredUser.ForgotPasswordToken),
-		LastLoginAt:         utils.GetInt64OrNil(registeredUser.LastLoginAt),
-		PasswordChangedAt:   utils.GetInt64OrNil(registeredUser.LastPasswordChangedAt),
-		IsDeleted:           registeredUser.IsDeleted,
-		LoginType:           registeredUser.UserLoginType,
+		Id:                registeredUser.Id,
+		Name:              registeredUser.Name,
+		Email:             utils.GetStringOrNil(registeredUser.Email),
+		Scopes:            registeredUser.Scopes,
+		LastLoginAt:       utils.GetInt64OrNil(registeredUser.LastLoginAt),
+		PasswordChangedAt: utils.GetInt64OrNil(registeredUser.LastPasswordChangedAt),
+		IsDeleted:         registeredUser.IsDeleted,
+		EmailVerified:     registeredUser.EmailVerified,
+		LoginType:         registeredUser.UserLoginType,
 	}, nil
 }
 
 func createUserEntity(profileInfo ProfileInfo) *entity2.User {
 	var entityLoginType enums.UserEntityLoginType
 	var email *string
+	var emailVerifiedAt *int64
 
+	// the provider has already verified the email it shares
 	if profileInfo.Email != "" {
 		entityLoginType = enums.UserEntityLoginTypeOauthAndOtp
 		email = &profileInfo.Email
+		timestamp := time.Now().UnixMilli()
+		emailVerifiedAt = &timestamp
 	} else {
 		entityLoginType = enums.UserEntityLoginTypeOauthOnly
 		email = nil
@@ -252,39 +250,42 @@ func createUserEntity(profileInfo ProfileInfo) *entity2.User {
 
 	return &entity2.User{
 		Id:                strings.ReplaceAll(uuid.New().String(), "-", ""),
-		OAuthId:           &profileInfo.OAuthId,
 		Email:             email,
 		ProfilePictureURL: &profileInfo.ProfilePicture,
 		Name:              profileInfo.Name,
 		UserLoginType:     entityLoginType,
+		EmailVerified:     email != nil,
+		EmailVerifiedAt:   emailVerifiedAt,
 		Scopes:            "user",
-		OAuthProvider:     &profileInfo.OAuthProvider,
 	}
 }
 
-func getProfileInfo(requestId string, request model.OAuthCallbackRequest) (*ProfileInfo, *model.ErrorResponse) {
-	oAuthProvider := request.Provider
+// function to exchange the authorization code with the provider of the request and fetch the profile of the user
+func fetchProfileInfo(
+	requestId string,
+	request model.OAuthCallbackRequest,
+	oAuthState *entity2.OAuthState,
+) (*ProfileInfo, OAuthProvider, *model.ErrorResponse) {
+	provider, exists := registry.Get(string(request.Provider))
 
-	var profileInfo ProfileInfo
+	if !exists {
+		return nil, nil, utils.BadRequestErrorResponse("Unsupported OAuth provider: " + string(request.Provider))
+	}
 
-	switch oAuthProvider {
-	case enums.OauthProviderGithub:
-		pInfo, err := FetchGitHubProfileInfo(requestId, request)
-		if err != nil {
-			return nil, err
-		}
-		profileInfo = *pInfo
-		profileInfo.OAuthProvider = string(enums.OauthProviderGithub)
+	tokens, err := provider.ExchangeCode(requestId, request.Code, oAuthState.CodeVerifier)
 
-	case enums.OauthProviderGoogle:
-		pInfo, err := FetchGoogleProfileInfo(requestId, request)
-		if err != nil {
-			return nil, err
-		}
-		profileInfo = *pInfo
-		profileInfo.OAuthProvider = string(enums.OauthProviderGoogle)
+	if err != nil {
+		return nil, nil, err
 	}
 
+	profileInfo, err := provider.FetchProfile(requestId, tokens, utils.GetStringOrNil(oAuthState.Nonce))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profileInfo.OAuthProvider = provider.Name()
+
 	if logger.IsDebugEnabled() {
 		logger.Debug(
 			"Profile info fetched",
@@ -293,40 +294,52 @@ func getProfileInfo(requestId string, request model.OAuthCallbackRequest) (*Prof
 		)
 	}
 
-	return &profileInfo, nil
+	return profileInfo, provider, nil
 }
 
-func getExistingUser(requestId string, profileInfo ProfileInfo) (*model.User, *model.ErrorResponse) {
-	user, err := authDao.GetUserByOAuthId(requestId, profileInfo.OAuthId)
+// function to find the user the provider identity is linked to. A 404 error is returned if the identity is not linked
+// and no account uses its email, so that a new user can be registered
+func getExistingUser(requestId string, profileInfo ProfileInfo) (*model.User, *entity2.UserIdentity, *model.ErrorResponse) {
+	identity, err := identityDao.GetUserIdentity(requestId, profileInfo.OAuthProvider, profileInfo.OAuthId)
 
 	if err != nil {
-		if err.ErrorCode == 404 {
-			if profileInfo.Email == "" {
-				return nil, &model.ErrorResponse{
-					ErrorCode: 404,
-				}
-			}
+		return nil, nil, err
+	}
 
-			userExistsByEmail, emailError := authDao.CheckIfUserExistsByEmail(requestId, profileInfo.Email)
+	if identity != nil {
+		user, err := authDao.GetUserById(requestId, identity.UserId)
 
-			if emailError != nil {
-				return nil, emailError
+		if err != nil {
+			// identities of deleted users are kept until the account is purged, they can't be used to register
+			if err.ErrorCode == 404 {
+				return nil, nil, utils.GetErrorResponse("This account is deleted. Use the link we emailed you to restore it", 403)
 			}
+			return nil, nil, err
+		}
 
-			if userExistsByEmail {
-				return nil, &model.ErrorResponse{
-					Message:   "An account exists by same email: " + profileInfo.Email,
-					ErrorCode: 409,
-				}
-			}
+		return user, identity, nil
+	}
+
+	if profileInfo.Email == "" {
+		return nil, nil, &model.ErrorResponse{
+			ErrorCode: 404,
 		}
+	}
 
-		return nil, err
+	userExistsByEmail, emailError := authDao.CheckIfUserExistsByEmail(requestId, profileInfo.Email)
+
+	if emailError != nil {
+		return nil, nil, emailError
 	}
 
-	if user != nil {
-		return user, nil
-	} else {
-		return nil, nil
+	if userExistsByEmail {
+		return nil, nil, &model.ErrorResponse{
+			Message:   "An account exists by same email: " + profileInfo.Email + ". Log in to it and link your " + profileInfo.OAuthProvider + " account from the account settings",
+			ErrorCode: 409,
+		}
+	}
+
+	return nil, nil, &model.ErrorResponse{
+		ErrorCode: 404,
 	}
 }
@@ -0,0 +1,263 @@
+package oauth_service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	enums "github.com/akgarg0472/urlshortener-auth-service/constants"
+	oauthDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/oauth"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	tokenService "github.com/akgarg0472/urlshortener-auth-service/internal/service/token"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// length of the random PKCE code verifier and nonce before encoding, RFC 7636 requires 43 to 128 characters once
+// encoded
+const codeVerifierBytes = 32
+
+// BuildAuthorizationUrl Function to start the OAuth flow with the provider. It creates the signed state and the PKCE
+// code verifier of the authorization request, stores them and returns the provider URL to send the browser to. The
+// returned browser binding must be set in a cookie, as the callback is only accepted from the browser holding it
+func BuildAuthorizationUrl(requestId string, provider string) (*model.OAuthAuthorizeResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Building OAuth authorization URL",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("provider", provider),
+		)
+	}
+
+	return startAuthorization(requestId, provider, enums.OAuthStatePurposeLogin, nil)
+}
+
+// function to create and store the authorization request. When linkUserId is set the identity the provider returns
+// is linked to that user, or used to re-authenticate it, instead of being logged in
+func startAuthorization(
+	requestId string,
+	providerName string,
+	purpose enums.OAuthStatePurpose,
+	linkUserId *string,
+) (*model.OAuthAuthorizeResponse, *model.ErrorResponse) {
+	provider, exists := registry.Get(providerName)
+
+	if !exists {
+		return nil, utils.GetErrorResponse("Unsupported OAuth provider: "+providerName, 404)
+	}
+
+	codeVerifier, err := generateRandomString()
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error generating PKCE code verifier",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	// the nonce binds the ID token issued by an OIDC provider to this authorization request
+	nonce, err := generateRandomString()
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error generating OAuth nonce",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	// login requests are bound to the browser which started them, so that the code and state of another user's flow
+	// can't be used to log a victim into that user's account. Link and re-authentication requests are bound to the user
+	var browserBinding string
+	var bindingHash *string
+
+	if linkUserId == nil {
+		browserBinding, err = generateRandomString()
+
+		if err != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error(
+					"Error generating OAuth browser binding",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(err),
+				)
+			}
+			return nil, utils.InternalServerErrorResponse()
+		}
+
+		hash := utils.HashToken(browserBinding)
+		bindingHash = &hash
+	}
+
+	validity := utils.GetEnvDurationSeconds("OAUTH_STATE_EXPIRY", 10*time.Minute)
+	expiresAt := time.Now().Add(validity)
+
+	oAuthState := &entity.OAuthState{
+		Id:           strings.ReplaceAll(uuid.New().String(), "-", ""),
+		Provider:     enums.OAuthProvider(provider.Name()),
+		Purpose:      purpose,
+		CodeVerifier: codeVerifier,
+		Nonce:        &nonce,
+		LinkUserId:   linkUserId,
+		BindingHash:  bindingHash,
+		ExpiresAt:    expiresAt.UnixMilli(),
+	}
+
+	state, stateError := tokenService.GetInstance().GenerateOAuthStateToken(requestId, oAuthState.Id, oAuthState.Provider, expiresAt.Unix())
+
+	if stateError != nil {
+		return nil, stateError
+	}
+
+	authorizationUrl, urlError := provider.BuildAuthUrl(requestId, AuthUrlParams{
+		State:         state,
+		CodeChallenge: generateCodeChallenge(codeVerifier),
+		Nonce:         nonce,
+	})
+
+	if urlError != nil {
+		return nil, urlError
+	}
+
+	if saveError := oauthDao.SaveOAuthState(requestId, oAuthState); saveError != nil {
+		return nil, saveError
+	}
+
+	return &model.OAuthAuthorizeResponse{
+		Success:          true,
+		AuthorizationUrl: authorizationUrl,
+		State:            state,
+		ExpiresAt:        expiresAt.UnixMilli(),
+		StatusCode:       200,
+		BrowserBinding:   browserBinding,
+	}, nil
+}
+
+// function to check the state returned by the provider against the stored authorization request and mark it used so
+// that it can't be replayed. The request must have been started for the purpose, by linkUserId to link an identity or
+// re-authenticate, or to log in when it is empty, in which case browserBinding must be the cookie set by the browser
+// which started it.
+// Returns the request holding the PKCE code verifier and the nonce
+func consumeOAuthState(
+	requestId string,
+	state string,
+	provider enums.OAuthProvider,
+	purpose enums.OAuthStatePurpose,
+	linkUserId string,
+	browserBinding string,
+) (*entity.OAuthState, *model.ErrorResponse) {
+	invalidStateError := utils.GetErrorResponse("Invalid or expired OAuth state", 400)
+
+	if state == "" {
+		return nil, invalidStateError
+	}
+
+	stateId, err := tokenService.GetInstance().ValidateOAuthStateToken(requestId, state, provider)
+
+	if err != nil {
+		return nil, err
+	}
+
+	oAuthState, err := oauthDao.GetOAuthState(requestId, stateId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if oAuthState == nil ||
+		oAuthState.Provider != provider ||
+		oAuthState.Purpose != purpose ||
+		utils.GetStringOrNil(oAuthState.LinkUserId) != linkUserId ||
+		!isBrowserBindingValid(*oAuthState, browserBinding) ||
+		oAuthState.UsedAt != nil ||
+		oAuthState.ExpiresAt < time.Now().UnixMilli() {
+		if logger.IsInfoEnabled() {
+			logger.Info(
+				"OAuth state is unknown, used, expired or issued for another flow",
+				zap.String(constants.RequestIdLogKey, requestId),
+			)
+		}
+		return nil, invalidStateError
+	}
+
+	marked, err := oauthDao.MarkOAuthStateUsed(requestId, oAuthState.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !marked {
+		if logger.IsInfoEnabled() {
+			logger.Info(
+				"OAuth state was consumed by another request",
+				zap.String(constants.RequestIdLogKey, requestId),
+			)
+		}
+		return nil, invalidStateError
+	}
+
+	return oAuthState, nil
+}
+
+// function to check the browser binding of the request. Requests without binding, i.e. started by an authenticated
+// user, must not be given one
+func isBrowserBindingValid(oAuthState entity.OAuthState, browserBinding string) bool {
+	if oAuthState.BindingHash == nil {
+		return browserBinding == ""
+	}
+
+	if browserBinding == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(*oAuthState.BindingHash), []byte(utils.HashToken(browserBinding))) == 1
+}
+
+// InitOAuthStatePurger periodically removes the expired authorization requests, which are created by an
+// unauthenticated endpoint
+func InitOAuthStatePurger() {
+	go func() {
+		purgeFrequency := utils.GetEnvDurationSeconds("OAUTH_STATE_PURGE_INTERVAL_SECONDS", 1*time.Hour)
+
+		for {
+			time.Sleep(purgeFrequency)
+
+			deleted, err := oauthDao.DeleteExpiredOAuthStates("")
+
+			if err == nil && logger.IsDebugEnabled() {
+				logger.Debug("Purged expired OAuth states",
+					zap.Int64("count", deleted),
+				)
+			}
+		}
+	}()
+}
+
+func generateRandomString() (string, error) {
+	randomBytes := make([]byte, codeVerifierBytes)
+
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+func generateCodeChallenge(codeVerifier string) string {
+	digest := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
@@ -0,0 +1,597 @@
+package oauth_service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	enums "github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+	// minimum time between two JWKS fetches triggered by an ID token signed with an unknown key
+	minJwksRefreshInterval = time.Minute
+	// allowed clock skew between the provider and the service when validating the ID token
+	idTokenLeeway = 30 * time.Second
+)
+
+var (
+	errUnknownSigningKey = errors.New("unknown ID token signing key")
+
+	// only asymmetric algorithms, the client secret must never be accepted as the ID token key
+	idTokenSigningMethods = []string{
+		jwt.SigningMethodRS256.Alg(),
+		jwt.SigningMethodRS384.Alg(),
+		jwt.SigningMethodRS512.Alg(),
+		jwt.SigningMethodPS256.Alg(),
+		jwt.SigningMethodPS384.Alg(),
+		jwt.SigningMethodPS512.Alg(),
+		jwt.SigningMethodES256.Alg(),
+		jwt.SigningMethodES384.Alg(),
+		jwt.SigningMethodES512.Alg(),
+	}
+)
+
+// OidcClaimMappings maps the fields of the profile to the claims of the ID token carrying them
+type OidcClaimMappings struct {
+	Id            string `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	EmailVerified string `json:"email_verified"`
+	Picture       string `json:"picture"`
+}
+
+type oidcDiscoveryDocument struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	JwksUri                           string   `json:"jwks_uri"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// OidcProvider is an identity provider integrated through OpenID Connect discovery. The discovery document and the
+// keys of the provider are fetched lazily and cached
+type OidcProvider struct {
+	providerConfig
+	Issuer        string
+	DiscoveryUrl  string
+	ClaimMappings OidcClaimMappings
+	HttpClient    *http.Client
+
+	mutex              sync.Mutex
+	discovery          *oidcDiscoveryDocument
+	discoveryFetchedAt time.Time
+	keys               map[string]any
+	keysFetchedAt      time.Time
+}
+
+// NewOidcProvider creates the provider from its row of the oauth_providers table. The discovery URL defaults to the
+// well-known configuration of the issuer and missing claim mappings default to the standard claims
+func NewOidcProvider(providerEntity entity.OAuthProvider, httpClient *http.Client) (*OidcProvider, error) {
+	providerEntity.Type = enums.OAuthProviderTypeOidc
+
+	provider := &OidcProvider{
+		providerConfig: newProviderConfig(providerEntity),
+		HttpClient:     httpClient,
+		ClaimMappings: OidcClaimMappings{
+			Id:            "sub",
+			Name:          "name",
+			Email:         "email",
+			EmailVerified: "email_verified",
+			Picture:       "picture",
+		},
+	}
+
+	if providerEntity.Issuer != nil {
+		provider.Issuer = strings.TrimSuffix(*providerEntity.Issuer, "/")
+	}
+
+	if providerEntity.DiscoveryUrl != nil && *providerEntity.DiscoveryUrl != "" {
+		provider.DiscoveryUrl = *providerEntity.DiscoveryUrl
+	} else if provider.Issuer != "" {
+		provider.DiscoveryUrl = provider.Issuer + oidcDiscoveryPath
+	} else {
+		return nil, fmt.Errorf("OIDC provider %s requires an issuer or a discovery URL", providerEntity.Provider)
+	}
+
+	if providerEntity.ClaimMappings != nil && *providerEntity.ClaimMappings != "" {
+		var mappings OidcClaimMappings
+
+		if err := json.Unmarshal([]byte(*providerEntity.ClaimMappings), &mappings); err != nil {
+			return nil, fmt.Errorf("invalid claim mappings of OIDC provider %s: %w", providerEntity.Provider, err)
+		}
+
+		provider.ClaimMappings.merge(mappings)
+	}
+
+	if !slices.Contains(strings.Fields(provider.scope), "openid") {
+		provider.scope = strings.TrimSpace("openid " + provider.scope)
+	}
+
+	return provider, nil
+}
+
+func (m *OidcClaimMappings) merge(overrides OidcClaimMappings) {
+	if overrides.Id != "" {
+		m.Id = overrides.Id
+	}
+	if overrides.Name != "" {
+		m.Name = overrides.Name
+	}
+	if overrides.Email != "" {
+		m.Email = overrides.Email
+	}
+	if overrides.EmailVerified != "" {
+		m.EmailVerified = overrides.EmailVerified
+	}
+	if overrides.Picture != "" {
+		m.Picture = overrides.Picture
+	}
+}
+
+func (p *OidcProvider) LoginMethod() enums.SessionLoginMethod {
+	return enums.SessionLoginMethodOidc
+}
+
+// BuildAuthUrl builds the authorization URL on the authorization endpoint advertised by the discovery document
+func (p *OidcProvider) BuildAuthUrl(requestId string, params AuthUrlParams) (string, *model.ErrorResponse) {
+	discovery, err := p.getDiscoveryDocument(requestId)
+
+	if err != nil {
+		return "", err
+	}
+
+	return p.buildAuthUrl(requestId, discovery.AuthorizationEndpoint, params, true)
+}
+
+// FetchProfile returns the profile carried by the ID token once its signature, issuer, audience, expiry and nonce
+// are verified
+func (p *OidcProvider) FetchProfile(requestId string, tokens *OAuthTokens, nonce string) (*ProfileInfo, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Fetching profile info from OIDC provider",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("provider", p.name),
+		)
+	}
+
+	discovery, err := p.getDiscoveryDocument(requestId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := p.verifyIdToken(requestId, discovery, tokens.IdToken, nonce)
+
+	if err != nil {
+		return nil, err
+	}
+
+	profileInfo := &ProfileInfo{
+		OAuthId:        getStringClaim(claims, p.ClaimMappings.Id),
+		Name:           getStringClaim(claims, p.ClaimMappings.Name),
+		ProfilePicture: getStringClaim(claims, p.ClaimMappings.Picture),
+	}
+
+	if profileInfo.OAuthId == "" {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"ID token does not carry the user id claim",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", p.name),
+				zap.String("claim", p.ClaimMappings.Id),
+			)
+		}
+		return nil, utils.GetErrorResponse("Invalid ID token", 401)
+	}
+
+	// the email is only trusted once the provider has verified it, as it is used to match existing accounts
+	if isClaimTrue(claims, p.ClaimMappings.EmailVerified) {
+		profileInfo.Email = getStringClaim(claims, p.ClaimMappings.Email)
+	}
+
+	return profileInfo, nil
+}
+
+func (p *OidcProvider) getDiscoveryDocument(requestId string) (*oidcDiscoveryDocument, *model.ErrorResponse) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	cacheTtl := utils.GetEnvDurationSeconds("OIDC_DISCOVERY_CACHE_TTL", time.Hour)
+
+	if p.discovery != nil && time.Since(p.discoveryFetchedAt) < cacheTtl {
+		return p.discovery, nil
+	}
+
+	var discovery oidcDiscoveryDocument
+
+	if err := p.getJson(p.DiscoveryUrl, &discovery); err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error fetching OIDC discovery document",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", p.name),
+				zap.String("discoveryUrl", p.DiscoveryUrl),
+				zap.Error(err),
+			)
+		}
+
+		// a stale document is better than failing every login while the provider is unreachable
+		if p.discovery != nil {
+			return p.discovery, nil
+		}
+
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	issuer := strings.TrimSuffix(discovery.Issuer, "/")
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JwksUri == "" ||
+		(p.Issuer != "" && issuer != p.Issuer) {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Invalid OIDC discovery document",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", p.name),
+				zap.String("issuer", discovery.Issuer),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	p.discovery = &discovery
+	p.discoveryFetchedAt = time.Now()
+
+	return p.discovery, nil
+}
+
+// ExchangeCode exchanges the authorization code at the token endpoint, authenticating with client_secret_basic unless
+// the provider only supports client_secret_post
+func (p *OidcProvider) ExchangeCode(requestId string, code string, codeVerifier string) (*OAuthTokens, *model.ErrorResponse) {
+	discovery, err := p.getDiscoveryDocument(requestId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.redirectUri)
+	form.Set("code_verifier", codeVerifier)
+
+	// client_secret_basic is the default authentication method of the token endpoint
+	useBasicAuth := len(discovery.TokenEndpointAuthMethodsSupported) == 0 ||
+		slices.Contains(discovery.TokenEndpointAuthMethodsSupported, "client_secret_basic")
+
+	if !useBasicAuth {
+		form.Set("client_id", p.clientId)
+		form.Set("client_secret", p.clientSecret)
+	}
+
+	req, reqErr := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+
+	if reqErr != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Failed to create OIDC token request",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(reqErr),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if useBasicAuth {
+		req.SetBasicAuth(url.QueryEscape(p.clientId), url.QueryEscape(p.clientSecret))
+	}
+
+	resp, respErr := p.HttpClient.Do(req)
+
+	if respErr != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Failed to get tokens from OIDC provider",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", p.name),
+				zap.Error(respErr),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		var response map[string]any
+		_ = json.NewDecoder(resp.Body).Decode(&response)
+
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Non 2xx status code received from OIDC token request",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", p.name),
+				zap.Int(constants.StatusCodeLogKey, resp.StatusCode),
+				zap.Any("response_body", response),
+			)
+		}
+		return nil, utils.GetErrorResponse("Invalid authorization code", 401)
+	}
+
+	var tokenResponse OAuthTokens
+
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil || tokenResponse.IdToken == "" {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"OIDC token response does not contain an ID token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", p.name),
+				zap.Error(err),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return &tokenResponse, nil
+}
+
+// function to verify the signature of the ID token against the keys of the provider and validate its issuer,
+// audience, expiry and nonce
+func (p *OidcProvider) verifyIdToken(
+	requestId string,
+	discovery *oidcDiscoveryDocument,
+	idToken string,
+	nonce string,
+) (jwt.MapClaims, *model.ErrorResponse) {
+	parsedToken, err := jwt.Parse(idToken, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getSigningKey(requestId, discovery.JwksUri, kid)
+	},
+		jwt.WithValidMethods(idTokenSigningMethods),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.clientId),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(idTokenLeeway),
+	)
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error validating ID token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", p.name),
+				zap.Error(err),
+			)
+		}
+		return nil, utils.GetErrorResponse("Invalid ID token", 401)
+	}
+
+	claims, _ := parsedToken.Claims.(jwt.MapClaims)
+	tokenNonce, _ := claims["nonce"].(string)
+
+	if nonce == "" || tokenNonce != nonce {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"ID token nonce does not match the authorization request",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", p.name),
+			)
+		}
+		return nil, utils.GetErrorResponse("Invalid ID token", 401)
+	}
+
+	// a token issued to several clients must name this client as its authorized party
+	if audience, _ := claims.GetAudience(); len(audience) > 1 {
+		if authorizedParty, _ := claims["azp"].(string); authorizedParty != p.clientId {
+			return nil, utils.GetErrorResponse("Invalid ID token", 401)
+		}
+	}
+
+	return claims, nil
+}
+
+// function to return the key of the provider with the kid. The JWKS is fetched again when the key is unknown, as the
+// provider may have rotated its keys, but not more often than minJwksRefreshInterval
+func (p *OidcProvider) getSigningKey(requestId string, jwksUri string, kid string) (any, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if key, found := p.findSigningKey(kid); found {
+		return key, nil
+	}
+
+	if time.Since(p.keysFetchedAt) < minJwksRefreshInterval {
+		return nil, errUnknownSigningKey
+	}
+
+	var jwks model.JWKSResponse
+
+	if err := p.getJson(jwksUri, &jwks); err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error fetching OIDC provider keys",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("provider", p.name),
+				zap.Error(err),
+			)
+		}
+		return nil, err
+	}
+
+	keys := make(map[string]any)
+
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		publicKey, err := parseJwk(jwk)
+
+		if err != nil {
+			if logger.IsWarnEnabled() {
+				logger.Warn(
+					"Skipping unsupported OIDC provider key",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.String("provider", p.name),
+					zap.String("kid", jwk.Kid),
+					zap.Error(err),
+				)
+			}
+			continue
+		}
+
+		keys[jwk.Kid] = publicKey
+	}
+
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+
+	if key, found := p.findSigningKey(kid); found {
+		return key, nil
+	}
+
+	return nil, errUnknownSigningKey
+}
+
+// function to find the key by kid. A token without kid is only accepted when the provider has a single key
+func (p *OidcProvider) findSigningKey(kid string) (any, bool) {
+	if kid == "" {
+		if len(p.keys) == 1 {
+			for _, key := range p.keys {
+				return key, true
+			}
+		}
+		return nil, false
+	}
+
+	key, found := p.keys[kid]
+	return key, found
+}
+
+func parseJwk(jwk model.JWK) (any, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", jwk.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+
+		if err != nil {
+			return nil, err
+		}
+
+		publicKey := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+
+		if !curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, errors.New("point is not on the curve")
+		}
+
+		return publicKey, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %s", jwk.Kty)
+}
+
+func (p *OidcProvider) getJson(endpoint string, target any) error {
+	resp, err := p.HttpClient.Get(endpoint)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// function to check a boolean claim, some providers send booleans as strings
+func isClaimTrue(claims jwt.MapClaims, claim string) bool {
+	switch value := claims[claim].(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	}
+
+	return false
+}
+
+func getStringClaim(claims jwt.MapClaims, claim string) string {
+	switch value := claims[claim].(type) {
+	case string:
+		return value
+	case float64:
+		return fmt.Sprintf("%.0f", value)
+	}
+
+	return ""
+}
@@ -0,0 +1,354 @@
+package oauth_service
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testOidcClientId     = "test-client"
+	testOidcClientSecret = "test-secret"
+	testOidcNonce        = "test-nonce"
+)
+
+// testOidcIssuer is an OIDC provider serving the discovery document, the JWKS and the token endpoint
+type testOidcIssuer struct {
+	server *httptest.Server
+
+	mutex             sync.Mutex
+	keys              map[string]*rsa.PrivateKey
+	jwksFetches       int
+	idToken           string
+	tokenCodeVerifier string
+}
+
+func newTestOidcIssuer(t *testing.T) *testOidcIssuer {
+	t.Helper()
+
+	issuer := &testOidcIssuer{keys: map[string]*rsa.PrivateKey{}}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                 issuer.server.URL,
+			"authorization_endpoint": issuer.server.URL + "/authorize",
+			"token_endpoint":         issuer.server.URL + "/token",
+			"jwks_uri":               issuer.server.URL + "/jwks",
+		})
+	})
+
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		issuer.mutex.Lock()
+		defer issuer.mutex.Unlock()
+
+		issuer.jwksFetches++
+
+		jwks := model.JWKSResponse{}
+
+		for kid, key := range issuer.keys {
+			jwks.Keys = append(jwks.Keys, model.JWK{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				Alg: "RS256",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+
+		_ = json.NewEncoder(w).Encode(jwks)
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientId, clientSecret, ok := r.BasicAuth()
+
+		if !ok || clientId != testOidcClientId || clientSecret != testOidcClientSecret || r.FormValue("code") != "valid-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		issuer.mutex.Lock()
+		defer issuer.mutex.Unlock()
+
+		issuer.tokenCodeVerifier = r.FormValue("code_verifier")
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"id_token":     issuer.idToken,
+		})
+	})
+
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (i *testOidcIssuer) addKey(t *testing.T, kid string) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.keys[kid] = key
+
+	return key
+}
+
+func (i *testOidcIssuer) removeKey(kid string) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	delete(i.keys, kid)
+}
+
+// function to return the claims of a valid ID token, which the cases alter
+func (i *testOidcIssuer) claims() jwt.MapClaims {
+	now := time.Now()
+
+	return jwt.MapClaims{
+		"iss":            i.server.URL,
+		"aud":            testOidcClientId,
+		"sub":            "user-123",
+		"name":           "Test User",
+		"email":          "user@example.com",
+		"email_verified": true,
+		"nonce":          testOidcNonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+	}
+}
+
+func signTestIdToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+
+	if err != nil {
+		t.Fatalf("signing ID token: %v", err)
+	}
+
+	return signed
+}
+
+func newTestOidcProvider(t *testing.T, issuer *testOidcIssuer) *OidcProvider {
+	t.Helper()
+
+	issuerUrl := issuer.server.URL
+	clientSecret := testOidcClientSecret
+
+	provider, err := NewOidcProvider(entity.OAuthProvider{
+		Provider:     "test-oidc",
+		ClientID:     testOidcClientId,
+		ClientSecret: &clientSecret,
+		RedirectURI:  "http://localhost/callback",
+		Issuer:       &issuerUrl,
+	}, issuer.server.Client())
+
+	if err != nil {
+		t.Fatalf("creating provider: %v", err)
+	}
+
+	return provider
+}
+
+func TestOidcProviderExchangeCodeAndFetchProfile(t *testing.T) {
+	issuer := newTestOidcIssuer(t)
+	key := issuer.addKey(t, "key-1")
+	issuer.idToken = signTestIdToken(t, key, "key-1", issuer.claims())
+	provider := newTestOidcProvider(t, issuer)
+
+	tokens, err := provider.ExchangeCode("test", "valid-code", "code-verifier")
+
+	if err != nil {
+		t.Fatalf("ExchangeCode returned error: %v", err.Message)
+	}
+
+	if issuer.tokenCodeVerifier != "code-verifier" {
+		t.Errorf("token endpoint received code_verifier %q, want %q", issuer.tokenCodeVerifier, "code-verifier")
+	}
+
+	profile, err := provider.FetchProfile("test", tokens, testOidcNonce)
+
+	if err != nil {
+		t.Fatalf("FetchProfile returned error: %v", err.Message)
+	}
+
+	if profile.OAuthId != "user-123" || profile.Name != "Test User" || profile.Email != "user@example.com" {
+		t.Errorf("unexpected profile %+v", profile)
+	}
+}
+
+func TestOidcProviderExchangeCodeRejectsInvalidCode(t *testing.T) {
+	issuer := newTestOidcIssuer(t)
+	provider := newTestOidcProvider(t, issuer)
+
+	if _, err := provider.ExchangeCode("test", "invalid-code", "code-verifier"); err == nil || err.ErrorCode != 401 {
+		t.Fatalf("ExchangeCode returned %v, want a 401 error", err)
+	}
+}
+
+func TestOidcProviderFetchProfileRejectsInvalidIdTokens(t *testing.T) {
+	issuer := newTestOidcIssuer(t)
+	key := issuer.addKey(t, "key-1")
+	otherKey, keyErr := rsa.GenerateKey(rand.Reader, 2048)
+
+	if keyErr != nil {
+		t.Fatalf("generating key: %v", keyErr)
+	}
+
+	testCases := []struct {
+		name   string
+		key    *rsa.PrivateKey
+		claims func(jwt.MapClaims)
+		nonce  string
+	}{
+		{
+			name:   "bad signature",
+			key:    otherKey,
+			claims: func(jwt.MapClaims) {},
+			nonce:  testOidcNonce,
+		},
+		{
+			name:   "wrong audience",
+			key:    key,
+			claims: func(claims jwt.MapClaims) { claims["aud"] = "other-client" },
+			nonce:  testOidcNonce,
+		},
+		{
+			name:   "wrong issuer",
+			key:    key,
+			claims: func(claims jwt.MapClaims) { claims["iss"] = "https://attacker.example.com" },
+			nonce:  testOidcNonce,
+		},
+		{
+			name:   "wrong nonce",
+			key:    key,
+			claims: func(jwt.MapClaims) {},
+			nonce:  "other-nonce",
+		},
+		{
+			name:   "missing nonce",
+			key:    key,
+			claims: func(claims jwt.MapClaims) { delete(claims, "nonce") },
+			nonce:  testOidcNonce,
+		},
+		{
+			name: "expired",
+			key:  key,
+			claims: func(claims jwt.MapClaims) {
+				claims["iat"] = time.Now().Add(-2 * time.Hour).Unix()
+				claims["exp"] = time.Now().Add(-time.Hour).Unix()
+			},
+			nonce: testOidcNonce,
+		},
+		{
+			name:   "missing expiry",
+			key:    key,
+			claims: func(claims jwt.MapClaims) { delete(claims, "exp") },
+			nonce:  testOidcNonce,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			provider := newTestOidcProvider(t, issuer)
+			claims := issuer.claims()
+			testCase.claims(claims)
+
+			tokens := &OAuthTokens{IdToken: signTestIdToken(t, testCase.key, "key-1", claims)}
+
+			profile, err := provider.FetchProfile("test", tokens, testCase.nonce)
+
+			if err == nil || err.ErrorCode != 401 {
+				t.Fatalf("FetchProfile returned profile %+v and error %v, want a 401 error", profile, err)
+			}
+		})
+	}
+}
+
+func TestOidcProviderFetchProfileRefetchesKeysAfterRotation(t *testing.T) {
+	issuer := newTestOidcIssuer(t)
+	oldKey := issuer.addKey(t, "key-1")
+	provider := newTestOidcProvider(t, issuer)
+
+	tokens := &OAuthTokens{IdToken: signTestIdToken(t, oldKey, "key-1", issuer.claims())}
+
+	if _, err := provider.FetchProfile("test", tokens, testOidcNonce); err != nil {
+		t.Fatalf("FetchProfile with the initial key returned error: %v", err.Message)
+	}
+
+	issuer.removeKey("key-1")
+	newKey := issuer.addKey(t, "key-2")
+	tokens = &OAuthTokens{IdToken: signTestIdToken(t, newKey, "key-2", issuer.claims())}
+
+	// the keys were just fetched, an unknown kid must not trigger another fetch yet
+	if _, err := provider.FetchProfile("test", tokens, testOidcNonce); err == nil {
+		t.Fatal("FetchProfile accepted a token signed with an unknown key before the refresh interval")
+	}
+
+	if issuer.jwksFetches != 1 {
+		t.Fatalf("JWKS fetched %d times within the refresh interval, want 1", issuer.jwksFetches)
+	}
+
+	provider.keysFetchedAt = time.Now().Add(-minJwksRefreshInterval)
+
+	if _, err := provider.FetchProfile("test", tokens, testOidcNonce); err != nil {
+		t.Fatalf("FetchProfile with the rotated key returned error: %v", err.Message)
+	}
+
+	if issuer.jwksFetches != 2 {
+		t.Errorf("JWKS fetched %d times, want 2", issuer.jwksFetches)
+	}
+}
+
+func TestOidcProviderFetchProfileIgnoresUnverifiedEmail(t *testing.T) {
+	issuer := newTestOidcIssuer(t)
+	key := issuer.addKey(t, "key-1")
+	provider := newTestOidcProvider(t, issuer)
+
+	for _, emailVerified := range []any{false, "false", nil} {
+		claims := issuer.claims()
+
+		if emailVerified == nil {
+			delete(claims, "email_verified")
+		} else {
+			claims["email_verified"] = emailVerified
+		}
+
+		tokens := &OAuthTokens{IdToken: signTestIdToken(t, key, "key-1", claims)}
+
+		profile, err := provider.FetchProfile("test", tokens, testOidcNonce)
+
+		if err != nil {
+			t.Fatalf("FetchProfile with email_verified %v returned error: %v", emailVerified, err.Message)
+		}
+
+		if profile.Email != "" {
+			t.Errorf("FetchProfile with email_verified %v returned email %q, want none", emailVerified, profile.Email)
+		}
+	}
+}
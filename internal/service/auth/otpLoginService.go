@@ -0,0 +1,194 @@
+package auth_service
+
+import (
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	authDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/auth"
+	otpDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/otp"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	notificationService "github.com/akgarg0472/urlshortener-auth-service/internal/service/notification"
+	throttleService "github.com/akgarg0472/urlshortener-auth-service/internal/service/throttle"
+	authModels "github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const invalidLoginOtpMessage = "Invalid or expired code"
+
+// RequestLoginOtp Function to email a one-time login code to users allowed to log in using OTP. The response is the
+// same whether the account exists or not so that it can't be used to discover registered emails
+func RequestLoginOtp(requestId string, loginOtpRequest authModels.LoginOtpRequest) (*authModels.LoginOtpResponse, *authModels.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Login OTP Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("email", loginOtpRequest.Email),
+		)
+	}
+
+	response := &authModels.LoginOtpResponse{
+		Success:    true,
+		Message:    "If an account supporting code login exists for " + loginOtpRequest.Email + ", we have sent a login code to it",
+		StatusCode: 200,
+	}
+
+	user, err := authDao.GetUserByEmail(requestId, loginOtpRequest.Email)
+
+	if err != nil {
+		if err.ErrorCode == 404 {
+			return response, nil
+		}
+		return nil, err
+	}
+
+	if !user.LoginType.AllowsOtp() {
+		if logger.IsInfoEnabled() {
+			logger.Info(
+				"User is not allowed to log in using OTP",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("loginType", string(user.LoginType)),
+			)
+		}
+		return response, nil
+	}
+
+	code, codeErr := utils.GenerateNumericCode(utils.GetEnvInt("LOGIN_OTP_LENGTH", 6))
+
+	if codeErr != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error generating login OTP",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(codeErr),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	codeHash, hashErr := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+
+	if hashErr != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error hashing login OTP",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(hashErr),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	validity := utils.GetEnvDurationSeconds("LOGIN_OTP_EXPIRY", 5*time.Minute)
+
+	saveError := otpDao.SaveLoginOtp(requestId, &entity.LoginOtp{
+		UserId:    user.Id,
+		CodeHash:  string(codeHash),
+		ExpiresAt: time.Now().Add(validity).UnixMilli(),
+	})
+
+	if saveError != nil {
+		return nil, saveError
+	}
+
+	notificationService.SendLoginOtpEmail(requestId, user.Email, user.Name, code, int64(validity.Minutes()))
+
+	return response, nil
+}
+
+// LoginWithOtp Function to log in the user using the one-time code sent by RequestLoginOtp
+func LoginWithOtp(
+	requestId string,
+	loginOtpVerifyRequest authModels.LoginOtpVerifyRequest,
+	clientInfo authModels.ClientInfo,
+) (*authModels.LoginResponse, *authModels.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing LoginWithOtp Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("email", loginOtpVerifyRequest.Email),
+		)
+	}
+
+	ipThrottleKey := throttleService.IpThrottleKey(clientInfo.IpAddress)
+
+	if throttleError := throttleService.CheckLoginAllowed(requestId, ipThrottleKey, false); throttleError != nil {
+		return nil, throttleError
+	}
+
+	user, err := authDao.GetUserByEmail(requestId, loginOtpVerifyRequest.Email)
+
+	if err != nil {
+		if err.ErrorCode == 404 {
+			_, _ = throttleService.RecordFailedLogin(requestId, ipThrottleKey, false)
+			return nil, utils.GetErrorResponse(invalidLoginOtpMessage, 401)
+		}
+		return nil, err
+	}
+
+	accountThrottleKey := throttleService.AccountThrottleKey(user.Id)
+
+	if throttleError := throttleService.CheckLoginAllowed(requestId, accountThrottleKey, true); throttleError != nil {
+		return nil, throttleError
+	}
+
+	loginOtp, err := otpDao.GetLoginOtp(requestId, user.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	attemptAllowed := false
+
+	if loginOtp != nil && time.Now().UnixMilli() <= loginOtp.ExpiresAt {
+		// the attempt is counted before the code is checked, so that parallel guesses can't exceed the maximum
+		attemptAllowed, err = otpDao.IncrementLoginOtpAttempts(requestId, user.Id, loginOtp.CodeHash, utils.GetEnvInt("LOGIN_OTP_MAX_ATTEMPTS", 5))
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !attemptAllowed {
+		if logger.IsInfoEnabled() {
+			logger.Info(
+				"No usable login OTP found",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("userId", user.Id),
+			)
+		}
+		throttleService.RecordFailedLoginAttempt(requestId, *user, clientInfo.IpAddress)
+		return nil, utils.GetErrorResponse(invalidLoginOtpMessage, 401)
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(loginOtp.CodeHash), []byte(loginOtpVerifyRequest.Code)) != nil {
+		if logger.IsInfoEnabled() {
+			logger.Info(
+				"Invalid login OTP provided",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("userId", user.Id),
+			)
+		}
+		throttleService.RecordFailedLoginAttempt(requestId, *user, clientInfo.IpAddress)
+		return nil, utils.GetErrorResponse(invalidLoginOtpMessage, 401)
+	}
+
+	consumed, err := otpDao.DeleteLoginOtp(requestId, user.Id, loginOtp.CodeHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// another request consumed the code in the meantime
+	if !consumed {
+		return nil, utils.GetErrorResponse(invalidLoginOtpMessage, 401)
+	}
+
+	throttleService.ResetLoginThrottle(requestId, accountThrottleKey)
+
+	markEmailVerifiedOnLogin(requestId, user)
+
+	return CompleteLogin(requestId, *user, constants.SessionLoginMethodOtp, clientInfo)
+}
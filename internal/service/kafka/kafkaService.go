@@ -2,6 +2,9 @@ package kafka_service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/akgarg0472/urlshortener-auth-service/constants"
 	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
@@ -12,11 +15,15 @@ import (
 )
 
 var (
-	instance                  *KafkaService
-	emailKafkaWriter          *kafka.Writer
-	userRegisteredKafkaWriter *kafka.Writer
-	emailNotificationTopic    = ""
-	userRegisteredTopic       = ""
+	instance                      *KafkaService
+	emailKafkaWriter              *kafka.Writer
+	userRegisteredKafkaWriter     *kafka.Writer
+	userProfileUpdatedKafkaWriter *kafka.Writer
+	userDeletedKafkaWriter        *kafka.Writer
+	emailNotificationTopic        = ""
+	userRegisteredTopic           = ""
+	userProfileUpdatedTopic       = ""
+	userDeletedTopic              = ""
 )
 
 type KafkaService struct {
@@ -48,16 +55,25 @@ func InitKafka() {
 	kafkaURL := utils.GetEnvVariable("KAFKA_CONNECTION_URL", "localhost:9092")
 	emailKafkaTopic := getEmailTopic()
 	userRegisteredKafkaTopic := getUserRegisteredTopic()
+	userProfileUpdatedKafkaTopic := getUserProfileUpdatedTopic()
+	userDeletedKafkaTopic := getUserDeletedTopic()
 
 	if logger.IsInfoEnabled() {
 		logger.Info("Initializing Kafka with url and topic(s)",
 			zap.String("kafka_url", kafkaURL),
-			zap.Strings("topics", []string{emailKafkaTopic, userRegisteredKafkaTopic}),
+			zap.Strings("topics", []string{emailKafkaTopic, userRegisteredKafkaTopic, userProfileUpdatedKafkaTopic, userDeletedKafkaTopic}),
 		)
 	}
 
 	emailKafkaWriter = getKafkaWriter(kafkaURL, emailKafkaTopic)
 	userRegisteredKafkaWriter = getKafkaWriter(kafkaURL, userRegisteredKafkaTopic)
+	userProfileUpdatedKafkaWriter = getKafkaWriter(kafkaURL, userProfileUpdatedKafkaTopic)
+	userDeletedKafkaWriter = getKafkaWriter(kafkaURL, userDeletedKafkaTopic)
+	// the user events are keyed by user, hashing the key keeps the events of a user in order on one partition
+	userProfileUpdatedKafkaWriter.Balancer = &kafka.Hash{}
+	userDeletedKafkaWriter.Balancer = &kafka.Hash{}
+	// the purge of a user is only committed once its event is acknowledged, so that it is retried if the event is lost
+	userDeletedKafkaWriter.Async = false
 
 	if logger.IsInfoEnabled() {
 		logger.Info("Kafka initialized (email)",
@@ -71,21 +87,56 @@ func InitKafka() {
 			zap.String("topic", userRegisteredKafkaWriter.Topic),
 		)
 	}
+	if logger.IsInfoEnabled() {
+		logger.Info("Kafka initialized (userProfileUpdated)",
+			zap.Any("clusterIP", userProfileUpdatedKafkaWriter.Addr),
+			zap.String("topic", userProfileUpdatedKafkaWriter.Topic),
+		)
+	}
+	if logger.IsInfoEnabled() {
+		logger.Info("Kafka initialized (userDeleted)",
+			zap.Any("clusterIP", userDeletedKafkaWriter.Addr),
+			zap.String("topic", userDeletedKafkaWriter.Topic),
+		)
+	}
 }
 
+// CloseKafka closes every writer, flushing the messages still buffered by the async writers. Returns the errors of all
+// the writers which failed to close
 func CloseKafka() error {
-	if emailKafkaWriter != nil {
-		logger.Debug("Closing email kafka connection")
-		kafkaCloseError := emailKafkaWriter.Close()
+	writers := []struct {
+		name   string
+		writer *kafka.Writer
+	}{
+		{"email", emailKafkaWriter},
+		{"userRegistered", userRegisteredKafkaWriter},
+		{"userProfileUpdated", userProfileUpdatedKafkaWriter},
+		{"userDeleted", userDeletedKafkaWriter},
+	}
+
+	var closeErrors []error
+
+	for _, w := range writers {
+		if w.writer == nil {
+			continue
+		}
+
+		logger.Debug("Closing kafka connection", zap.String("writer", w.name))
+		kafkaCloseError := w.writer.Close()
+
 		if logger.IsInfoEnabled() {
-			logger.Info("Email Kafka connection closed",
+			logger.Info("Kafka connection closed",
+				zap.String("writer", w.name),
 				zap.Bool("status", kafkaCloseError == nil),
 			)
 		}
-		return kafkaCloseError
+
+		if kafkaCloseError != nil {
+			closeErrors = append(closeErrors, fmt.Errorf("closing %s kafka writer: %w", w.name, kafkaCloseError))
+		}
 	}
 
-	return nil
+	return errors.Join(closeErrors...)
 }
 
 func getEmailTopic() string {
@@ -108,6 +159,17 @@ func getUserRegisteredTopic() string {
 	return userRegisteredTopic
 }
 
+// unlike the other topics, this one has a default as it was added after the service was deployed
+func getUserProfileUpdatedTopic() string {
+	userProfileUpdatedTopic = utils.GetEnvVariable("KAFKA_TOPIC_USER_PROFILE_UPDATED", "user.profile.updated")
+	return userProfileUpdatedTopic
+}
+
+func getUserDeletedTopic() string {
+	userDeletedTopic = utils.GetEnvVariable("KAFKA_TOPIC_USER_DELETED", "user.deleted")
+	return userDeletedTopic
+}
+
 func (kafkaService *KafkaService) PushNotificationEvent(reqId string, event model.NotificationEvent) {
 	if logger.IsDebugEnabled() {
 		logger.Debug(
@@ -191,3 +253,95 @@ func (kafkaService *KafkaService) PushUserRegisteredEvent(reqId string, userId s
 		}
 	}
 }
+
+func (kafkaService *KafkaService) PushUserProfileUpdatedEvent(reqId string, event model.UserProfileUpdatedEvent) {
+	if logger.IsDebugEnabled() {
+		logger.Debug(
+			"Pushing user Profile Updated Event To Kafka",
+			zap.String(constants.RequestIdLogKey, reqId),
+			zap.String("topic", userProfileUpdatedTopic),
+			zap.String("userId", event.UserId),
+			zap.Strings("updatedFields", event.UpdatedFields),
+		)
+	}
+
+	pushUserEvent(reqId, userProfileUpdatedKafkaWriter, event.UserId, event)
+}
+
+// PublishUserDeletedEvent publishes the event synchronously and returns the error if the broker did not acknowledge it
+func (kafkaService *KafkaService) PublishUserDeletedEvent(reqId string, event model.UserDeletedEvent) error {
+	if logger.IsDebugEnabled() {
+		logger.Debug(
+			"Publishing user Deleted Event To Kafka",
+			zap.String(constants.RequestIdLogKey, reqId),
+			zap.String("topic", userDeletedTopic),
+			zap.String("userId", event.UserId),
+		)
+	}
+
+	if userDeletedKafkaWriter == nil {
+		return errors.New("user deleted kafka writer is not initialized")
+	}
+
+	msgBytes, msgError := utils.ConvertToJsonBytes(event)
+
+	if msgError != nil {
+		return msgError
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), utils.GetEnvDurationSeconds("KAFKA_PUBLISH_TIMEOUT_SECONDS", 10*time.Second))
+	defer cancel()
+
+	msgWriteErr := userDeletedKafkaWriter.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(event.UserId),
+		Value: msgBytes,
+	})
+
+	if msgWriteErr != nil && logger.IsErrorEnabled() {
+		logger.Error(
+			"Error publishing user deleted event",
+			zap.String(constants.RequestIdLogKey, reqId),
+			zap.String("userId", event.UserId),
+			zap.Error(msgWriteErr),
+		)
+	}
+
+	return msgWriteErr
+}
+
+// function to push the event of the user keyed by its id, the writer hashes the key so that the events of a user
+// stay in order on one partition
+func pushUserEvent(reqId string, writer *kafka.Writer, userId string, event any) {
+	if writer == nil {
+		return
+	}
+
+	msgBytes, msgError := utils.ConvertToJsonBytes(event)
+
+	if msgError != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error converting user event to bytes",
+				zap.String(constants.RequestIdLogKey, reqId),
+				zap.String("topic", writer.Topic),
+				zap.Error(msgError),
+			)
+		}
+		return
+	}
+
+	message := kafka.Message{
+		Key:   []byte(userId),
+		Value: msgBytes,
+	}
+
+	msgWriteErr := writer.WriteMessages(context.Background(), message)
+
+	if logger.IsDebugEnabled() {
+		logger.Debug(
+			"Kafka push result",
+			zap.String(constants.RequestIdLogKey, reqId),
+			zap.Bool("success", msgWriteErr == nil),
+		)
+	}
+}
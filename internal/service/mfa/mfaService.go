@@ -0,0 +1,352 @@
+package mfa_service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	mfaDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/mfa"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+)
+
+const recoveryCodesCount = 10
+
+// EnrollTotp generates a new TOTP secret for the user. MFA stays disabled till the enrollment is confirmed with a
+// code generated from the secret
+func EnrollTotp(requestId string, authClaims model.AuthClaims) (*model.TotpEnrollmentResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Enrolling TOTP",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+		)
+	}
+
+	userMfa, err := mfaDao.GetUserMfa(requestId, authClaims.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if userMfa != nil && userMfa.Enabled {
+		return nil, utils.GetErrorResponse("Two-factor authentication is already enabled", 409)
+	}
+
+	secret, secretErr := generateTotpSecret()
+
+	if secretErr != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error generating TOTP secret",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(secretErr),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	encryptedSecret, encryptErr := encryptSecret(requestId, secret)
+
+	if encryptErr != nil {
+		return nil, encryptErr
+	}
+
+	if userMfa == nil {
+		userMfa = &entity.UserMfa{UserId: authClaims.UserId}
+	}
+
+	userMfa.EncryptedSecret = encryptedSecret
+	userMfa.RecoveryCodes = ""
+	userMfa.LastUsedStep = 0
+
+	if err := mfaDao.SaveUserMfa(requestId, userMfa); err != nil {
+		return nil, err
+	}
+
+	otpauthUri := buildOtpauthUri(utils.GetEnvVariable("MFA_TOTP_ISSUER", "UrlShortener"), authClaims.Email, secret)
+
+	return &model.TotpEnrollmentResponse{
+		Secret:     secret,
+		OtpauthUri: otpauthUri,
+		QrPayload:  otpauthUri,
+		Success:    true,
+		StatusCode: 200,
+	}, nil
+}
+
+// ConfirmTotp enables MFA once the user proves the authenticator app is set up and returns the recovery codes
+func ConfirmTotp(requestId string, authClaims model.AuthClaims, totpCodeRequest model.TotpCodeRequest) (*model.TotpConfirmResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Confirming TOTP enrollment",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+		)
+	}
+
+	userMfa, err := mfaDao.GetUserMfa(requestId, authClaims.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if userMfa == nil {
+		return nil, utils.GetErrorResponse("Two-factor authentication enrollment not started", 400)
+	}
+
+	if userMfa.Enabled {
+		return nil, utils.GetErrorResponse("Two-factor authentication is already enabled", 409)
+	}
+
+	if err := verifyTotpCode(requestId, userMfa, totpCodeRequest.Code); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, recoveryCodeHashes, recoveryErr := generateRecoveryCodes(requestId)
+
+	if recoveryErr != nil {
+		return nil, recoveryErr
+	}
+
+	enabledAt := time.Now().UnixMilli()
+	userMfa.Enabled = true
+	userMfa.EnabledAt = &enabledAt
+	userMfa.RecoveryCodes = recoveryCodeHashes
+
+	if err := mfaDao.SaveUserMfa(requestId, userMfa); err != nil {
+		return nil, err
+	}
+
+	return &model.TotpConfirmResponse{
+		RecoveryCodes: recoveryCodes,
+		Success:       true,
+		Message:       "Two-factor authentication enabled successfully",
+		StatusCode:    200,
+	}, nil
+}
+
+// DisableTotp turns MFA off after checking a TOTP or recovery code
+func DisableTotp(requestId string, authClaims model.AuthClaims, totpCodeRequest model.TotpCodeRequest) (*model.MfaResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Disabling TOTP",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+		)
+	}
+
+	if err := VerifyMfaCode(requestId, authClaims.UserId, totpCodeRequest.Code); err != nil {
+		return nil, err
+	}
+
+	if err := mfaDao.DeleteUserMfa(requestId, authClaims.UserId); err != nil {
+		return nil, err
+	}
+
+	return &model.MfaResponse{
+		Success:    true,
+		Message:    "Two-factor authentication disabled successfully",
+		StatusCode: 200,
+	}, nil
+}
+
+// IsMfaEnabled checks if the user has confirmed the MFA enrollment
+func IsMfaEnabled(requestId string, userId string) (bool, *model.ErrorResponse) {
+	userMfa, err := mfaDao.GetUserMfa(requestId, userId)
+
+	if err != nil {
+		return false, err
+	}
+
+	return userMfa != nil && userMfa.Enabled, nil
+}
+
+// VerifyMfaCode accepts either a TOTP code or one of the unused recovery codes of the user. Recovery codes are
+// consumed on use
+func VerifyMfaCode(requestId string, userId string, code string) *model.ErrorResponse {
+	userMfa, err := mfaDao.GetUserMfa(requestId, userId)
+
+	if err != nil {
+		return err
+	}
+
+	if userMfa == nil || !userMfa.Enabled {
+		return utils.GetErrorResponse("Two-factor authentication is not enabled", 400)
+	}
+
+	code = strings.TrimSpace(code)
+
+	if len(code) == totpDigits {
+		return verifyTotpCode(requestId, userMfa, code)
+	}
+
+	return consumeRecoveryCode(requestId, userMfa, code)
+}
+
+func verifyTotpCode(requestId string, userMfa *entity.UserMfa, code string) *model.ErrorResponse {
+	secret, err := decryptSecret(requestId, userMfa.EncryptedSecret)
+
+	if err != nil {
+		return err
+	}
+
+	step, matched := matchTotpCode(secret, strings.TrimSpace(code), time.Now())
+
+	if !matched || step <= userMfa.LastUsedStep {
+		if logger.IsInfoEnabled() {
+			logger.Info("Invalid TOTP code provided",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("userId", userMfa.UserId),
+			)
+		}
+		return utils.GetErrorResponse("Invalid two-factor authentication code", 401)
+	}
+
+	updated, err := mfaDao.UpdateLastUsedStep(requestId, userMfa.UserId, step)
+
+	if err != nil {
+		return err
+	}
+
+	// another request used the same code in the meantime
+	if !updated {
+		return utils.GetErrorResponse("Invalid two-factor authentication code", 401)
+	}
+
+	userMfa.LastUsedStep = step
+
+	return nil
+}
+
+func consumeRecoveryCode(requestId string, userMfa *entity.UserMfa, code string) *model.ErrorResponse {
+	codeHash := utils.HashToken(normalizeRecoveryCode(code))
+	codeHashes := strings.Split(userMfa.RecoveryCodes, ",")
+	index := slices.Index(codeHashes, codeHash)
+
+	if userMfa.RecoveryCodes == "" || index < 0 {
+		if logger.IsInfoEnabled() {
+			logger.Info("Invalid recovery code provided",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("userId", userMfa.UserId),
+			)
+		}
+		return utils.GetErrorResponse("Invalid two-factor authentication code", 401)
+	}
+
+	remainingHashes := strings.Join(slices.Delete(slices.Clone(codeHashes), index, index+1), ",")
+
+	replaced, err := mfaDao.ReplaceRecoveryCodes(requestId, userMfa.UserId, userMfa.RecoveryCodes, remainingHashes)
+
+	if err != nil {
+		return err
+	}
+
+	if !replaced {
+		return utils.GetErrorResponse("Invalid two-factor authentication code", 401)
+	}
+
+	if logger.IsInfoEnabled() {
+		logger.Info("Recovery code used",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", userMfa.UserId),
+			zap.Int("remaining", len(codeHashes)-1),
+		)
+	}
+
+	userMfa.RecoveryCodes = remainingHashes
+
+	return nil
+}
+
+// generateRecoveryCodes returns the recovery codes to show to the user along with their comma separated digests
+func generateRecoveryCodes(requestId string) ([]string, string, *model.ErrorResponse) {
+	recoveryCodes := make([]string, 0, recoveryCodesCount)
+	codeHashes := make([]string, 0, recoveryCodesCount)
+
+	for range recoveryCodesCount {
+		randomBytes := make([]byte, 5)
+
+		if _, err := rand.Read(randomBytes); err != nil {
+			if logger.IsErrorEnabled() {
+				logger.Error("Error generating recovery codes",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.Error(err),
+				)
+			}
+			return nil, "", utils.InternalServerErrorResponse()
+		}
+
+		code := strings.ToLower(totpSecretEncoding.EncodeToString(randomBytes))
+		recoveryCodes = append(recoveryCodes, code[:4]+"-"+code[4:])
+		codeHashes = append(codeHashes, utils.HashToken(code))
+	}
+
+	return recoveryCodes, strings.Join(codeHashes, ","), nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+func encryptSecret(requestId string, secret string) (string, *model.ErrorResponse) {
+	key, err := getEncryptionKey(requestId)
+
+	if err != nil {
+		return "", err
+	}
+
+	encryptedSecret, encryptErr := utils.EncryptAESGCM(key, secret)
+
+	if encryptErr != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error encrypting TOTP secret",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(encryptErr),
+			)
+		}
+		return "", utils.InternalServerErrorResponse()
+	}
+
+	return encryptedSecret, nil
+}
+
+func decryptSecret(requestId string, encryptedSecret string) (string, *model.ErrorResponse) {
+	key, err := getEncryptionKey(requestId)
+
+	if err != nil {
+		return "", err
+	}
+
+	secret, decryptErr := utils.DecryptAESGCM(key, encryptedSecret)
+
+	if decryptErr != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error decrypting TOTP secret",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(decryptErr),
+			)
+		}
+		return "", utils.InternalServerErrorResponse()
+	}
+
+	return secret, nil
+}
+
+// getEncryptionKey returns the AES-256 key used to encrypt TOTP secrets at rest, configured as base64 encoded value
+func getEncryptionKey(requestId string) ([]byte, *model.ErrorResponse) {
+	key, err := base64.StdEncoding.DecodeString(utils.GetEnvVariable("MFA_ENCRYPTION_KEY", ""))
+
+	if err != nil || len(key) != 32 {
+		if logger.IsErrorEnabled() {
+			logger.Error("MFA_ENCRYPTION_KEY must be a base64 encoded 32 bytes key",
+				zap.String(constants.RequestIdLogKey, requestId),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return key, nil
+}
@@ -0,0 +1,94 @@
+package mfa_service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	totpDigits      = 6
+	totpPeriod      = 30
+	totpSkewSteps   = 1
+	totpSecretBytes = 20
+)
+
+var totpSecretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateTotpSecret returns a random base32 encoded secret as expected by authenticator apps
+func generateTotpSecret() (string, error) {
+	secret := make([]byte, totpSecretBytes)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpSecretEncoding.EncodeToString(secret), nil
+}
+
+// generateTotpCode computes the RFC 6238 code of the secret for the given time step
+func generateTotpCode(secret string, step int64) (string, error) {
+	key, err := totpSecretEncoding.DecodeString(strings.ToUpper(secret))
+
+	if err != nil {
+		return "", err
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000), nil
+}
+
+// matchTotpCode checks the code against the current time step and its neighbours to tolerate clock drift. Returns
+// the time step the code belongs to
+func matchTotpCode(secret string, code string, now time.Time) (int64, bool) {
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	currentStep := now.Unix() / totpPeriod
+
+	for skew := -totpSkewSteps; skew <= totpSkewSteps; skew++ {
+		step := currentStep + int64(skew)
+		expectedCode, err := generateTotpCode(secret, step)
+
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expectedCode), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// buildOtpauthUri builds the key URI understood by authenticator apps, which is also the payload of the QR code
+func buildOtpauthUri(issuer string, accountName string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(accountName)
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
@@ -1,6 +1,8 @@
 package notification_service
 
 import (
+	"time"
+
 	"github.com/akgarg0472/urlshortener-auth-service/constants"
 	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
 	kafka_service "github.com/akgarg0472/urlshortener-auth-service/internal/service/kafka"
@@ -44,6 +46,26 @@ func SendForgotPasswordEmail(
 	kafka_service.GetInstance().PushNotificationEvent(requestId, *event)
 }
 
+func SendSetPasswordEmail(
+	requestId string,
+	email string,
+	name string,
+	setPasswordUrl string,
+) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Pushing set password email",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("email", email),
+		)
+	}
+
+	body := utils.GenerateSetPasswordEmailBody(email, name, setPasswordUrl)
+	recipients := [1]string{email}
+	event := generateNotificationEvent(recipients[:], "Set a password for your UrlShortener account", body, true, constants.NotificationTypeEmail)
+
+	kafka_service.GetInstance().PushNotificationEvent(requestId, *event)
+}
+
 func SendPasswordChangeSuccessEmail(requestId string, email string) {
 	if logger.IsInfoEnabled() {
 		logger.Info("Pushing password changed success email",
@@ -59,6 +81,141 @@ func SendPasswordChangeSuccessEmail(requestId string, email string) {
 	kafka_service.GetInstance().PushNotificationEvent(requestId, *event)
 }
 
+func SendAccountLockedEmail(requestId string, email string, lockedUntil int64) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Pushing account locked email",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("email", email),
+		)
+	}
+
+	body := utils.GenerateAccountLockedEmailBody(email, time.UnixMilli(lockedUntil).UTC().Format(time.RFC1123))
+	recipients := [1]string{email}
+	event := generateNotificationEvent(recipients[:], "Your UrlShortener account has been temporarily locked", body, true, constants.NotificationTypeEmail)
+
+	kafka_service.GetInstance().PushNotificationEvent(requestId, *event)
+}
+
+func SendLoginOtpEmail(requestId string, email string, name string, code string, validityMinutes int64) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Pushing login OTP email",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("email", email),
+		)
+	}
+
+	body := utils.GenerateLoginOtpEmailBody(name, code, validityMinutes)
+	recipients := [1]string{email}
+	event := generateNotificationEvent(recipients[:], "Your UrlShortener login code", body, true, constants.NotificationTypeEmail)
+
+	kafka_service.GetInstance().PushNotificationEvent(requestId, *event)
+}
+
+func SendMagicLinkEmail(requestId string, email string, name string, magicLink string, validityMinutes int64) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Pushing magic link email",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("email", email),
+		)
+	}
+
+	body := utils.GenerateMagicLinkEmailBody(name, magicLink, validityMinutes)
+	recipients := [1]string{email}
+	event := generateNotificationEvent(recipients[:], "Your UrlShortener login link", body, true, constants.NotificationTypeEmail)
+
+	kafka_service.GetInstance().PushNotificationEvent(requestId, *event)
+}
+
+func SendEmailVerificationEmail(requestId string, email string, name string, verificationLink string, validityHours int64) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Pushing email verification email",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("email", email),
+		)
+	}
+
+	body := utils.GenerateEmailVerificationEmailBody(name, verificationLink, validityHours)
+	recipients := [1]string{email}
+	event := generateNotificationEvent(recipients[:], "Verify your UrlShortener email address", body, true, constants.NotificationTypeEmail)
+
+	kafka_service.GetInstance().PushNotificationEvent(requestId, *event)
+}
+
+func SendAccountDeletedEmail(requestId string, email string, name string, restoreLink string, graceDays int64) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Pushing account deleted email",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("email", email),
+		)
+	}
+
+	body := utils.GenerateAccountDeletedEmailBody(name, restoreLink, graceDays)
+	recipients := [1]string{email}
+	event := generateNotificationEvent(recipients[:], "Your UrlShortener account has been deleted", body, true, constants.NotificationTypeEmail)
+
+	kafka_service.GetInstance().PushNotificationEvent(requestId, *event)
+}
+
+func SendDataExportReadyEmail(requestId string, email string, name string, downloadLink string, validityHours int64) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Pushing data export ready email",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("email", email),
+		)
+	}
+
+	body := utils.GenerateDataExportReadyEmailBody(name, downloadLink, validityHours)
+	recipients := [1]string{email}
+	event := generateNotificationEvent(recipients[:], "Your UrlShortener data export is ready", body, true, constants.NotificationTypeEmail)
+
+	kafka_service.GetInstance().PushNotificationEvent(requestId, *event)
+}
+
+func SendEmailChangeConfirmEmail(requestId string, email string, name string, confirmLink string, validityHours int64) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Pushing email change confirmation email",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("email", email),
+		)
+	}
+
+	body := utils.GenerateEmailChangeConfirmEmailBody(name, confirmLink, validityHours)
+	recipients := [1]string{email}
+	event := generateNotificationEvent(recipients[:], "Confirm your new UrlShortener email", body, true, constants.NotificationTypeEmail)
+
+	kafka_service.GetInstance().PushNotificationEvent(requestId, *event)
+}
+
+func SendEmailChangeRequestedEmail(requestId string, email string, name string, newEmail string, cancelLink string) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Pushing email change requested email",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("email", email),
+		)
+	}
+
+	body := utils.GenerateEmailChangeRequestedEmailBody(name, newEmail, cancelLink)
+	recipients := [1]string{email}
+	event := generateNotificationEvent(recipients[:], "Email change requested for your UrlShortener account", body, true, constants.NotificationTypeEmail)
+
+	kafka_service.GetInstance().PushNotificationEvent(requestId, *event)
+}
+
+func SendEmailChangedEmail(requestId string, email string, name string, newEmail string) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Pushing email changed email",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("email", email),
+		)
+	}
+
+	body := utils.GenerateEmailChangedEmailBody(name, newEmail)
+	recipients := [1]string{email}
+	event := generateNotificationEvent(recipients[:], "The email of your UrlShortener account was changed", body, true, constants.NotificationTypeEmail)
+
+	kafka_service.GetInstance().PushNotificationEvent(requestId, *event)
+}
+
 func generateNotificationEvent(
 	recipients []string,
 	subject string,
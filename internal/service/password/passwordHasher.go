@@ -0,0 +1,253 @@
+package password_service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	HashAlgorithmBcrypt   = "bcrypt"
+	HashAlgorithmArgon2id = "argon2id"
+)
+
+var (
+	hasher     PasswordHasher
+	hasherOnce sync.Once
+
+	errInvalidPasswordHash = errors.New("invalid password hash")
+)
+
+// PasswordHasher hashes passwords into self-describing PHC format strings, e.g.
+// $argon2id$v=19$m=19456,t=2,p=1$<salt>$<hash>. bcrypt uses its own $2a$<cost>$ format
+type PasswordHasher interface {
+	// Hash returns the encoded hash of the password
+	Hash(password string) (string, error)
+	// Verify checks the password against the encoded hash, which must be produced by the same algorithm
+	Verify(password string, encodedHash string) (bool, error)
+	// Supports reports whether the encoded hash was produced by the algorithm of the hasher
+	Supports(encodedHash string) bool
+	// NeedsRehash reports whether the encoded hash was produced by the algorithm using different parameters
+	NeedsRehash(encodedHash string) bool
+}
+
+func getHasher() PasswordHasher {
+	hasherOnce.Do(func() {
+		hasher = newConfiguredHasher(utils.GetEnvVariable("PASSWORD_HASH_ALGORITHM", HashAlgorithmArgon2id))
+	})
+
+	return hasher
+}
+
+func newConfiguredHasher(algorithm string) PasswordHasher {
+	bcryptPasswordHasher := &bcryptHasher{
+		cost: utils.GetEnvInt("PASSWORD_BCRYPT_COST", 12),
+	}
+
+	switch algorithm {
+	case HashAlgorithmBcrypt:
+		return bcryptPasswordHasher
+
+	case HashAlgorithmArgon2id:
+		return &argon2idHasher{
+			memory:      uint32(utils.GetEnvInt("PASSWORD_ARGON2_MEMORY_KIB", 19456)),
+			iterations:  uint32(utils.GetEnvInt("PASSWORD_ARGON2_ITERATIONS", 2)),
+			parallelism: uint8(utils.GetEnvInt("PASSWORD_ARGON2_PARALLELISM", 1)),
+			saltLength:  uint32(utils.GetEnvInt("PASSWORD_ARGON2_SALT_LENGTH", 16)),
+			keyLength:   uint32(utils.GetEnvInt("PASSWORD_ARGON2_KEY_LENGTH", 32)),
+		}
+
+	default:
+		if logger.IsFatalEnabled() {
+			logger.Fatal("Unsupported password hash algorithm",
+				zap.String("algorithm", algorithm),
+			)
+		}
+		panic(fmt.Sprintf("Unsupported password hash algorithm: %s", algorithm))
+	}
+}
+
+// HashPassword hashes the password using the configured algorithm
+func HashPassword(password string) (string, error) {
+	return getHasher().Hash(password)
+}
+
+// VerifyPassword checks the password against the encoded hash, whichever supported algorithm produced it
+func VerifyPassword(password string, encodedHash string) bool {
+	var algorithmHasher PasswordHasher
+
+	configuredHasher := getHasher()
+
+	if configuredHasher.Supports(encodedHash) {
+		algorithmHasher = configuredHasher
+	} else {
+		for _, supportedHasher := range []PasswordHasher{&bcryptHasher{}, &argon2idHasher{}} {
+			if supportedHasher.Supports(encodedHash) {
+				algorithmHasher = supportedHasher
+				break
+			}
+		}
+	}
+
+	if algorithmHasher == nil {
+		return false
+	}
+
+	matched, err := algorithmHasher.Verify(password, encodedHash)
+
+	return err == nil && matched
+}
+
+// NeedsRehash reports whether the encoded hash should be replaced by a hash using the configured algorithm and
+// parameters
+func NeedsRehash(encodedHash string) bool {
+	configuredHasher := getHasher()
+	return !configuredHasher.Supports(encodedHash) || configuredHasher.NeedsRehash(encodedHash)
+}
+
+type bcryptHasher struct {
+	cost int
+}
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+func (h *bcryptHasher) Verify(password string, encodedHash string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password))
+
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (h *bcryptHasher) Supports(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$2a$") || strings.HasPrefix(encodedHash, "$2b$") || strings.HasPrefix(encodedHash, "$2y$")
+}
+
+func (h *bcryptHasher) NeedsRehash(encodedHash string) bool {
+	cost, err := bcrypt.Cost([]byte(encodedHash))
+	return err != nil || cost != h.cost
+}
+
+type argon2idHasher struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	saltLength  uint32
+	keyLength   uint32
+}
+
+type argon2idParams struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+func (h *argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.saltLength)
+
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.iterations, h.memory, h.parallelism, h.keyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.memory,
+		h.iterations,
+		h.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *argon2idHasher) Verify(password string, encodedHash string) (bool, error) {
+	params, err := decodeArgon2idHash(encodedHash)
+
+	if err != nil {
+		return false, err
+	}
+
+	key := argon2.IDKey([]byte(password), params.salt, params.iterations, params.memory, params.parallelism, uint32(len(params.key)))
+
+	return subtle.ConstantTimeCompare(key, params.key) == 1, nil
+}
+
+func (h *argon2idHasher) Supports(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$argon2id$")
+}
+
+func (h *argon2idHasher) NeedsRehash(encodedHash string) bool {
+	params, err := decodeArgon2idHash(encodedHash)
+
+	if err != nil {
+		return true
+	}
+
+	return params.memory != h.memory ||
+		params.iterations != h.iterations ||
+		params.parallelism != h.parallelism ||
+		uint32(len(params.salt)) != h.saltLength ||
+		uint32(len(params.key)) != h.keyLength
+}
+
+// function to parse the $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key> PHC string
+func decodeArgon2idHash(encodedHash string) (*argon2idParams, error) {
+	parts := strings.Split(encodedHash, "$")
+
+	if len(parts) != 6 || parts[1] != HashAlgorithmArgon2id {
+		return nil, errInvalidPasswordHash
+	}
+
+	var version int
+
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, errInvalidPasswordHash
+	}
+
+	params := &argon2idParams{}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return nil, errInvalidPasswordHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+
+	if err != nil {
+		return nil, errInvalidPasswordHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+
+	if err != nil || len(key) == 0 {
+		return nil, errInvalidPasswordHash
+	}
+
+	params.salt = salt
+	params.key = key
+
+	return params, nil
+}
@@ -0,0 +1,84 @@
+package password_service
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	passwordDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/password"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// CheckPasswordReuse rejects the password if it matches the current password of the user or any of the previous ones
+// kept in the password history. A history depth of 0 disables the check
+func CheckPasswordReuse(requestId string, user model.User, password string) *model.ErrorResponse {
+	depth := getPolicy().HistoryDepth
+
+	if depth <= 0 {
+		return nil
+	}
+
+	passwordHashes := make([]string, 0, depth+1)
+
+	// users registered before the password history was introduced only have their current password
+	if user.Password != "" {
+		passwordHashes = append(passwordHashes, user.Password)
+	}
+
+	passwordHistory, err := passwordDao.GetPasswordHistory(requestId, user.Id, depth)
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range passwordHistory {
+		if entry.PasswordHash != user.Password {
+			passwordHashes = append(passwordHashes, entry.PasswordHash)
+		}
+	}
+
+	for _, passwordHash := range passwordHashes {
+		if !VerifyPassword(password, passwordHash) {
+			continue
+		}
+
+		if logger.IsInfoEnabled() {
+			logger.Info("Password matches a previous password of user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("userId", user.Id),
+			)
+		}
+
+		return &model.ErrorResponse{
+			Message:   "Password does not meet the password policy",
+			ErrorCode: 400,
+			Errors: []model.PasswordPolicyViolation{
+				{
+					Rule:    "password_history",
+					Message: "Password must not match any of your last " + strconv.Itoa(depth) + " passwords",
+				},
+			},
+		}
+	}
+
+	return nil
+}
+
+// RecordPasswordHistory adds the newly set password hash of the user to the password history
+func RecordPasswordHistory(requestId string, userId string, passwordHash string) {
+	depth := getPolicy().HistoryDepth
+
+	if depth <= 0 {
+		return
+	}
+
+	_ = passwordDao.SavePasswordHistory(requestId, &entity.PasswordHistory{
+		Id:           strings.ReplaceAll(uuid.New().String(), "-", ""),
+		UserId:       userId,
+		PasswordHash: passwordHash,
+	}, depth)
+}
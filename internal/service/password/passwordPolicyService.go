@@ -0,0 +1,244 @@
+package password_service
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+)
+
+var (
+	policy     *PasswordPolicy
+	policyOnce sync.Once
+)
+
+const (
+	// minimum length of the email local part or name word checked against the password, shorter ones match too often
+	minPersonalInfoLength = 3
+	// bcrypt only uses the first 72 bytes of the password
+	maxBcryptPasswordBytes = 72
+)
+
+type PasswordPolicy struct {
+	MinLength            int
+	MaxLength            int
+	RequireUppercase     bool
+	RequireLowercase     bool
+	RequireDigit         bool
+	RequireSymbol        bool
+	DisallowPersonalInfo bool
+	BlockedPasswords     map[string]struct{}
+	HistoryDepth         int
+}
+
+func getPolicy() *PasswordPolicy {
+	policyOnce.Do(func() {
+		policy = &PasswordPolicy{
+			MinLength:            utils.GetEnvInt("PASSWORD_MIN_LENGTH", 8),
+			MaxLength:            utils.GetEnvInt("PASSWORD_MAX_LENGTH", 72),
+			RequireUppercase:     utils.GetEnvBool("PASSWORD_REQUIRE_UPPERCASE", true),
+			RequireLowercase:     utils.GetEnvBool("PASSWORD_REQUIRE_LOWERCASE", true),
+			RequireDigit:         utils.GetEnvBool("PASSWORD_REQUIRE_DIGIT", true),
+			RequireSymbol:        utils.GetEnvBool("PASSWORD_REQUIRE_SYMBOL", false),
+			DisallowPersonalInfo: utils.GetEnvBool("PASSWORD_DISALLOW_PERSONAL_INFO", true),
+			BlockedPasswords:     loadBlockedPasswords(utils.GetEnvVariable("PASSWORD_BLOCKLIST_FILE", "")),
+			HistoryDepth:         utils.GetEnvInt("PASSWORD_HISTORY_DEPTH", 5),
+		}
+
+		if policy.MaxLength <= 0 || policy.MaxLength > maxBcryptPasswordBytes {
+			policy.MaxLength = maxBcryptPasswordBytes
+		}
+	})
+
+	return policy
+}
+
+// ValidatePassword checks the password against the configured policy. The violated rules are returned in the Errors
+// of the error response
+func ValidatePassword(requestId string, password string, email string, name string) *model.ErrorResponse {
+	violations := getPolicy().Check(password, email, name)
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if logger.IsInfoEnabled() {
+		logger.Info("Password violates the password policy",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.Any("violations", violations),
+		)
+	}
+
+	return &model.ErrorResponse{
+		Message:   "Password does not meet the password policy",
+		ErrorCode: 400,
+		Errors:    violations,
+	}
+}
+
+// Check returns every rule of the policy violated by the password of the user with the email and name
+func (p *PasswordPolicy) Check(password string, email string, name string) []model.PasswordPolicyViolation {
+	violations := make([]model.PasswordPolicyViolation, 0)
+
+	length := len([]rune(password))
+
+	if length < p.MinLength {
+		violations = append(violations, model.PasswordPolicyViolation{
+			Rule:    "min_length",
+			Message: "Password must be at least " + strconv.Itoa(p.MinLength) + " characters long",
+		})
+	}
+
+	if length > p.MaxLength || len(password) > maxBcryptPasswordBytes {
+		violations = append(violations, model.PasswordPolicyViolation{
+			Rule:    "max_length",
+			Message: "Password must be at most " + strconv.Itoa(p.MaxLength) + " characters long",
+		})
+	}
+
+	hasUppercase, hasLowercase, hasDigit, hasSymbol := classifyCharacters(password)
+
+	if p.RequireUppercase && !hasUppercase {
+		violations = append(violations, model.PasswordPolicyViolation{
+			Rule:    "uppercase",
+			Message: "Password must contain an uppercase letter",
+		})
+	}
+
+	if p.RequireLowercase && !hasLowercase {
+		violations = append(violations, model.PasswordPolicyViolation{
+			Rule:    "lowercase",
+			Message: "Password must contain a lowercase letter",
+		})
+	}
+
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, model.PasswordPolicyViolation{
+			Rule:    "digit",
+			Message: "Password must contain a digit",
+		})
+	}
+
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, model.PasswordPolicyViolation{
+			Rule:    "symbol",
+			Message: "Password must contain a special character",
+		})
+	}
+
+	if p.DisallowPersonalInfo && containsPersonalInfo(password, email, name) {
+		violations = append(violations, model.PasswordPolicyViolation{
+			Rule:    "personal_info",
+			Message: "Password must not contain your email or name",
+		})
+	}
+
+	if _, blocked := p.BlockedPasswords[strings.ToLower(password)]; blocked {
+		violations = append(violations, model.PasswordPolicyViolation{
+			Rule:    "common_password",
+			Message: "Password is too common or has appeared in a data breach",
+		})
+	}
+
+	return violations
+}
+
+func classifyCharacters(password string) (bool, bool, bool, bool) {
+	var hasUppercase, hasLowercase, hasDigit, hasSymbol bool
+
+	for _, character := range password {
+		switch {
+		case unicode.IsUpper(character):
+			hasUppercase = true
+		case unicode.IsLower(character):
+			hasLowercase = true
+		case unicode.IsDigit(character):
+			hasDigit = true
+		case unicode.IsPunct(character) || unicode.IsSymbol(character) || unicode.IsSpace(character):
+			hasSymbol = true
+		}
+	}
+
+	return hasUppercase, hasLowercase, hasDigit, hasSymbol
+}
+
+// function to check if the password contains the local part of the email or any word of the name
+func containsPersonalInfo(password string, email string, name string) bool {
+	lowerPassword := strings.ToLower(password)
+	personalInfo := strings.Fields(strings.ToLower(name))
+
+	if localPart, _, found := strings.Cut(strings.ToLower(email), "@"); found {
+		personalInfo = append(personalInfo, localPart)
+	}
+
+	for _, info := range personalInfo {
+		if len([]rune(info)) >= minPersonalInfoLength && strings.Contains(lowerPassword, info) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// function to load the blocked passwords from the file having one password per line. Lines starting with # are ignored
+func loadBlockedPasswords(path string) map[string]struct{} {
+	blockedPasswords := make(map[string]struct{})
+
+	if path == "" {
+		return blockedPasswords
+	}
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error opening password blocklist file",
+				zap.String("path", path),
+				zap.Error(err),
+			)
+		}
+		return blockedPasswords
+	}
+
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		blockedPasswords[strings.ToLower(line)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error reading password blocklist file",
+				zap.String("path", path),
+				zap.Error(err),
+			)
+		}
+	}
+
+	if logger.IsInfoEnabled() {
+		logger.Info("Loaded password blocklist",
+			zap.String("path", path),
+			zap.Int("passwords", len(blockedPasswords)),
+		)
+	}
+
+	return blockedPasswords
+}
@@ -0,0 +1,153 @@
+package session_service
+
+import (
+	"strings"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	sessionDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/session"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	tokenService "github.com/akgarg0472/urlshortener-auth-service/internal/service/token"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// StartSession records a new session for the user and issues the access and refresh tokens bound to it
+func StartSession(
+	requestId string,
+	user model.User,
+	loginMethod constants.SessionLoginMethod,
+	clientInfo model.ClientInfo,
+) (*model.SessionTokens, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Starting session",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", user.Id),
+			zap.String("loginMethod", string(loginMethod)),
+		)
+	}
+
+	session := &entity.Session{
+		Id:          strings.ReplaceAll(uuid.New().String(), "-", ""),
+		UserId:      user.Id,
+		UserAgent:   clientInfo.UserAgent,
+		IpAddress:   clientInfo.IpAddress,
+		LoginMethod: loginMethod,
+	}
+
+	if err := sessionDao.SaveSession(requestId, session); err != nil {
+		return nil, err
+	}
+
+	jwtToken, jwtError := tokenService.GetInstance().GenerateJwtToken(requestId, user, session.Id)
+
+	if jwtError != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error generating auth token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Int16(constants.ErrorCodeLogKey, jwtError.ErrorCode),
+				zap.Any(constants.ErrorMessageLogKey, jwtError.Message),
+			)
+		}
+		return nil, jwtError
+	}
+
+	// the refresh token family shares the id of the session so that both can be revoked together
+	refreshToken, refreshTokenError := tokenService.GetInstance().GenerateRefreshToken(requestId, user.Id, session.Id)
+
+	if refreshTokenError != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error generating refresh token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Int16(constants.ErrorCodeLogKey, refreshTokenError.ErrorCode),
+				zap.Any(constants.ErrorMessageLogKey, refreshTokenError.Message),
+			)
+		}
+		return nil, refreshTokenError
+	}
+
+	return &model.SessionTokens{
+		SessionId:    session.Id,
+		AccessToken:  jwtToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// ListSessions returns the active sessions of the authenticated user
+func ListSessions(requestId string, authClaims model.AuthClaims) (*model.SessionsResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Listing sessions",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+		)
+	}
+
+	sessions, err := sessionDao.GetActiveSessionsByUserId(requestId, authClaims.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sessionInfos := make([]model.SessionInfo, 0, len(sessions))
+
+	for _, session := range sessions {
+		sessionInfos = append(sessionInfos, model.SessionInfo{
+			Id:          session.Id,
+			UserAgent:   session.UserAgent,
+			IpAddress:   session.IpAddress,
+			LoginMethod: string(session.LoginMethod),
+			CreatedAt:   session.CreatedAt,
+			LastSeenAt:  session.LastSeenAt,
+			Current:     session.Id == authClaims.SessionId,
+		})
+	}
+
+	return &model.SessionsResponse{
+		Sessions:   sessionInfos,
+		Success:    true,
+		StatusCode: 200,
+	}, nil
+}
+
+// RevokeSession ends one of the sessions of the authenticated user
+func RevokeSession(requestId string, authClaims model.AuthClaims, sessionId string) (*model.SessionResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Revoking session of user",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+			zap.String("sessionId", sessionId),
+		)
+	}
+
+	session, err := sessionDao.GetSessionById(requestId, sessionId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// sessions of other users are reported as missing to not leak their existence
+	if session.UserId != authClaims.UserId {
+		return nil, utils.GetErrorResponse("Session not found", 404)
+	}
+
+	if session.RevokedAt == nil {
+		if err := tokenService.GetInstance().RevokeSession(requestId, sessionId); err != nil {
+			return nil, err
+		}
+	}
+
+	return &model.SessionResponse{
+		Success:    true,
+		Message:    "Session revoked successfully",
+		StatusCode: 200,
+	}, nil
+}
+
+// TouchSession records the activity of the session, sessions which no longer exist are ignored
+func TouchSession(requestId string, sessionId string) {
+	sessionDao.UpdateSessionLastSeen(requestId, sessionId)
+}
@@ -0,0 +1,181 @@
+package throttle_service
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	authDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/auth"
+	throttleDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/throttle"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+)
+
+var (
+	config     *loginThrottleConfig
+	configOnce sync.Once
+)
+
+type loginThrottleConfig struct {
+	accountLockThreshold int
+	ipLockThreshold      int
+	lockDuration         time.Duration
+	backoffBase          time.Duration
+	backoffMax           time.Duration
+}
+
+func getConfig() *loginThrottleConfig {
+	configOnce.Do(func() {
+		config = &loginThrottleConfig{
+			accountLockThreshold: utils.GetEnvInt("LOGIN_LOCKOUT_THRESHOLD", 5),
+			ipLockThreshold:      utils.GetEnvInt("LOGIN_IP_LOCKOUT_THRESHOLD", 50),
+			lockDuration:         utils.GetEnvDurationSeconds("LOGIN_LOCKOUT_DURATION_SECONDS", 15*time.Minute),
+			backoffBase:          utils.GetEnvDurationSeconds("LOGIN_BACKOFF_BASE_SECONDS", 1*time.Second),
+			backoffMax:           utils.GetEnvDurationSeconds("LOGIN_BACKOFF_MAX_SECONDS", 30*time.Second),
+		}
+	})
+
+	return config
+}
+
+// AccountThrottleKey returns the key tracking the failed logins of an account
+func AccountThrottleKey(userId string) string {
+	return constants.AccountThrottleKeyPrefix + userId
+}
+
+// IpThrottleKey returns the key tracking the failed logins from a source IP address
+func IpThrottleKey(ipAddress string) string {
+	return constants.IpThrottleKeyPrefix + ipAddress
+}
+
+// CheckLoginAllowed rejects the login attempt if the key is locked or, for account keys, still waiting for its backoff
+// delay to pass. IP keys are only locked once their threshold is reached, as a backoff would delay every user sharing
+// the IP, e.g. behind a NAT, after a few failures of one of them
+func CheckLoginAllowed(requestId string, throttleKey string, isAccountKey bool) *model.ErrorResponse {
+	throttle, err := throttleDao.GetLoginThrottle(requestId, throttleKey)
+
+	if err != nil || throttle == nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	allowedAt := throttle.LockedUntil
+
+	if isAccountKey {
+		allowedAt = max(allowedAt, throttle.LastFailedAt+getBackoffDelay(throttle.FailedAttempts).Milliseconds())
+	}
+
+	if allowedAt <= now {
+		return nil
+	}
+
+	if logger.IsInfoEnabled() {
+		logger.Info("Login attempt throttled",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("throttleKey", throttleKey),
+			zap.Int("failedAttempts", throttle.FailedAttempts),
+			zap.Int64("allowedAt", allowedAt),
+		)
+	}
+
+	return &model.ErrorResponse{
+		Message:    "Too many failed login attempts. Please try again later",
+		ErrorCode:  429,
+		RetryAfter: int64(math.Ceil(float64(allowedAt-now) / 1000)),
+	}
+}
+
+// RecordFailedLogin increments the failed attempts of the key and locks it once the threshold is reached. Returns
+// the time till which the key is locked if the lock was applied by this attempt, zero otherwise
+func RecordFailedLogin(requestId string, throttleKey string, isAccountKey bool) (int64, *model.ErrorResponse) {
+	threshold := getConfig().ipLockThreshold
+
+	if isAccountKey {
+		threshold = getConfig().accountLockThreshold
+	}
+
+	var lockedUntil int64
+
+	throttle, err := throttleDao.RecordFailedLoginAttempt(requestId, throttleKey, func(throttle *entity.LoginThrottle) {
+		now := time.Now().UnixMilli()
+		lockDuration := getConfig().lockDuration.Milliseconds()
+
+		// failures older than the lock duration and expired locks are forgotten
+		if (throttle.LockedUntil > 0 && now >= throttle.LockedUntil) || now-throttle.LastFailedAt > lockDuration {
+			throttle.FailedAttempts = 0
+			throttle.LockedUntil = 0
+		}
+
+		throttle.FailedAttempts++
+		throttle.LastFailedAt = now
+
+		if throttle.LockedUntil == 0 && threshold > 0 && throttle.FailedAttempts >= threshold {
+			throttle.LockedUntil = now + lockDuration
+			lockedUntil = throttle.LockedUntil
+		}
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	if lockedUntil > 0 && logger.IsWarnEnabled() {
+		logger.Warn("Login locked after too many failed attempts",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("throttleKey", throttleKey),
+			zap.Int("failedAttempts", throttle.FailedAttempts),
+			zap.Int64("lockedUntil", lockedUntil),
+		)
+	}
+
+	return lockedUntil, nil
+}
+
+// ResetLoginThrottle clears the failed attempts of the key after a successful login
+func ResetLoginThrottle(requestId string, throttleKey string) {
+	_ = throttleDao.DeleteLoginThrottle(requestId, throttleKey)
+}
+
+// UnlockAccount clears the lock and failed login attempts of the account
+func UnlockAccount(requestId string, userId string) (*model.UnlockAccountResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Unlocking account",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", userId),
+		)
+	}
+
+	if _, err := authDao.GetUserById(requestId, userId); err != nil {
+		return nil, err
+	}
+
+	if err := throttleDao.DeleteLoginThrottle(requestId, AccountThrottleKey(userId)); err != nil {
+		return nil, err
+	}
+
+	return &model.UnlockAccountResponse{
+		Success:    true,
+		Message:    "Account unlocked successfully",
+		StatusCode: 200,
+	}, nil
+}
+
+// getBackoffDelay returns the delay to wait after the given number of consecutive failures, doubling with every
+// failure till the configured maximum
+func getBackoffDelay(failedAttempts int) time.Duration {
+	if failedAttempts <= 0 {
+		return 0
+	}
+
+	delay := getConfig().backoffBase
+
+	for i := 1; i < failedAttempts && delay < getConfig().backoffMax; i++ {
+		delay *= 2
+	}
+
+	return min(delay, getConfig().backoffMax)
+}
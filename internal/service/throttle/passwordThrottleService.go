@@ -0,0 +1,56 @@
+package throttle_service
+
+import (
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	notificationService "github.com/akgarg0472/urlshortener-auth-service/internal/service/notification"
+	passwordService "github.com/akgarg0472/urlshortener-auth-service/internal/service/password"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"go.uber.org/zap"
+)
+
+// VerifyPasswordWithThrottle checks the password of the user while enforcing the login throttle of the account. A wrong
+// password is recorded as a failed login, and a successful check resets the throttle. Returns false if the password is
+// wrong so that the caller can answer with its own message, or an error if the account is throttled
+func VerifyPasswordWithThrottle(
+	requestId string,
+	user model.User,
+	password string,
+	clientInfo model.ClientInfo,
+) (bool, *model.ErrorResponse) {
+	accountThrottleKey := AccountThrottleKey(user.Id)
+
+	if throttleError := CheckLoginAllowed(requestId, accountThrottleKey, true); throttleError != nil {
+		return false, throttleError
+	}
+
+	if !passwordService.VerifyPassword(password, user.Password) {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Invalid password provided",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("userId", user.Id),
+			)
+		}
+
+		RecordFailedLoginAttempt(requestId, user, clientInfo.IpAddress)
+
+		return false, nil
+	}
+
+	ResetLoginThrottle(requestId, accountThrottleKey)
+
+	return true, nil
+}
+
+// RecordFailedLoginAttempt records the failed login against both the account and the source IP, notifying the user
+// when the account gets locked
+func RecordFailedLoginAttempt(requestId string, user model.User, ipAddress string) {
+	lockedUntil, _ := RecordFailedLogin(requestId, AccountThrottleKey(user.Id), true)
+
+	if lockedUntil > 0 && user.Email != "" {
+		notificationService.SendAccountLockedEmail(requestId, user.Email, lockedUntil)
+	}
+
+	_, _ = RecordFailedLogin(requestId, IpThrottleKey(ipAddress), false)
+}
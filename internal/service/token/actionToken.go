@@ -0,0 +1,126 @@
+package token_service
+
+import (
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	actionDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/action"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+)
+
+// IssueActionToken generates a single-use token allowing the user to perform the action, e.g. through an emailed
+// link. Only the digest of the token is stored, and issuing a new token invalidates the pending ones of the action
+func (tokenService *TokenService) IssueActionToken(
+	requestId string,
+	userId string,
+	action constants.ActionTokenType,
+	validity time.Duration,
+	payload *string,
+) (string, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Issuing action token",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", userId),
+			zap.String("action", string(action)),
+		)
+	}
+
+	rawToken, err := utils.GenerateSecureRandomToken(32)
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error while generating action token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return "", utils.InternalServerErrorResponse()
+	}
+
+	actionToken := &entity.ActionToken{
+		Id:        generateId(),
+		UserId:    userId,
+		Action:    action,
+		TokenHash: utils.HashToken(rawToken),
+		Payload:   payload,
+		ExpiresAt: time.Now().Add(validity).UnixMilli(),
+	}
+
+	if saveError := actionDao.SaveActionToken(requestId, actionToken); saveError != nil {
+		return "", saveError
+	}
+
+	return rawToken, nil
+}
+
+// ValidateActionToken returns the stored action token if it is unused and not expired, without consuming it
+func (tokenService *TokenService) ValidateActionToken(
+	requestId string,
+	action constants.ActionTokenType,
+	rawToken string,
+) (*entity.ActionToken, *model.ErrorResponse) {
+	actionToken, err := actionDao.GetActionTokenByHash(requestId, action, utils.HashToken(rawToken))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if actionToken == nil || actionToken.UsedAt != nil || time.Now().UnixMilli() > actionToken.ExpiresAt {
+		if logger.IsInfoEnabled() {
+			logger.Info("Invalid, used or expired action token presented",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("action", string(action)),
+			)
+		}
+		return nil, utils.GetErrorResponse("Invalid or expired token", 400)
+	}
+
+	return actionToken, nil
+}
+
+// ConsumeActionToken validates the action token and marks it used so that it can't be used again
+func (tokenService *TokenService) ConsumeActionToken(
+	requestId string,
+	action constants.ActionTokenType,
+	rawToken string,
+) (*entity.ActionToken, *model.ErrorResponse) {
+	actionToken, err := tokenService.ValidateActionToken(requestId, action, rawToken)
+
+	if err != nil {
+		return nil, err
+	}
+
+	marked, err := actionDao.MarkActionTokenUsed(requestId, actionToken.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// another request used the token in the meantime
+	if !marked {
+		return nil, utils.GetErrorResponse("Invalid or expired token", 400)
+	}
+
+	return actionToken, nil
+}
+
+// InvalidateActionTokens invalidates the pending tokens issued to the user for the action
+func (tokenService *TokenService) InvalidateActionTokens(
+	requestId string,
+	userId string,
+	action constants.ActionTokenType,
+) *model.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Invalidating action tokens",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", userId),
+			zap.String("action", string(action)),
+		)
+	}
+
+	return actionDao.InvalidateActionTokens(requestId, userId, action)
+}
@@ -0,0 +1,63 @@
+package token_service
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"go.uber.org/zap"
+)
+
+// AuthenticateIntrospectionClient checks the client credentials of a client calling the introspection endpoint
+func (tokenService *TokenService) AuthenticateIntrospectionClient(clientId string, clientSecret string) bool {
+	expectedSecret, found := tokenService.introspectionClients[clientId]
+
+	if !found {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(expectedSecret), []byte(clientSecret)) == 1
+}
+
+// IntrospectToken returns the state of the token as defined by RFC 7662. Invalid, expired and revoked tokens are
+// reported as inactive without any further details
+func (tokenService *TokenService) IntrospectToken(requestId string, token string, clientId string) *model.IntrospectionResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Introspecting token",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("clientId", clientId),
+		)
+	}
+
+	_, claims, parseError := tokenService.parseJwtToken(requestId, token)
+
+	if parseError != nil {
+		return &model.IntrospectionResponse{Active: false}
+	}
+
+	if revocationError := tokenService.checkJwtTokenRevocation(requestId, claims); revocationError != nil {
+		return &model.IntrospectionResponse{Active: false}
+	}
+
+	authClaims := mapClaimsToAuthClaims(claims)
+	issuer, _ := claims["iss"].(string)
+	tokenClientId, _ := claims["client_id"].(string)
+
+	if tokenClientId == "" {
+		tokenClientId = tokenService.jwtClientId
+	}
+
+	return &model.IntrospectionResponse{
+		Active:    true,
+		Sub:       authClaims.Email,
+		UserId:    authClaims.UserId,
+		Scope:     strings.Join(strings.Split(authClaims.Scopes, ","), " "),
+		Exp:       authClaims.ExpiresAt,
+		Iat:       authClaims.IssuedAt,
+		Iss:       issuer,
+		ClientId:  tokenClientId,
+		TokenType: "Bearer",
+	}
+}
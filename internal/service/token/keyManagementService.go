@@ -0,0 +1,121 @@
+package token_service
+
+import (
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	tokenDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/token"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// ListSigningKeys returns every key of the key ring
+func (tokenService *TokenService) ListSigningKeys(requestId string) *model.SigningKeysResponse {
+	tokenService.keyRing.reload(requestId)
+
+	return &model.SigningKeysResponse{
+		Keys:       tokenService.keyRing.describe(),
+		Success:    true,
+		StatusCode: 200,
+	}
+}
+
+// AddSigningKey adds a new verification key to the key ring. The key is not used for signing until it is promoted
+func (tokenService *TokenService) AddSigningKey(requestId string, request model.AddSigningKeyRequest) (*model.SigningKeyResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Adding signing key",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.Any("request", request),
+		)
+	}
+
+	// HS256 secrets are never sent over the API, they are rotated through JWT_KEY_ID and JWT_PREVIOUS_SECRET_KEYS
+	if request.Algorithm == jwt.SigningMethodHS256.Alg() {
+		return nil, utils.BadRequestErrorResponse("HS256 keys are configured through JWT_SECRET_KEY and JWT_PREVIOUS_SECRET_KEYS")
+	}
+
+	// make sure the key can be loaded before anyone relies on it
+	if _, err := LoadSigningKeyFromPEM(request.Kid, request.Algorithm, request.PrivateKeyPath); err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error loading signing key",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return nil, &model.ErrorResponse{
+			Message:   "Invalid signing key",
+			ErrorCode: 400,
+			Errors:    err.Error(),
+		}
+	}
+
+	err := tokenDao.SaveSigningKey(requestId, &entity.SigningKey{
+		Kid:            request.Kid,
+		Algorithm:      request.Algorithm,
+		PrivateKeyPath: request.PrivateKeyPath,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	tokenService.keyRing.reload(requestId)
+
+	return &model.SigningKeyResponse{
+		Success:    true,
+		Message:    "Signing key added successfully",
+		StatusCode: 201,
+	}, nil
+}
+
+// PromoteSigningKey makes the key the current signing key. The previous key remains valid for verification
+func (tokenService *TokenService) PromoteSigningKey(requestId string, kid string) (*model.SigningKeyResponse, *model.ErrorResponse) {
+	if _, err := tokenService.keyRing.verificationKey(kid); err != nil {
+		return nil, utils.GetErrorResponse("Signing key not found or retired", 404)
+	}
+
+	if err := tokenDao.SetCurrentSigningKey(requestId, kid); err != nil {
+		return nil, err
+	}
+
+	tokenService.keyRing.reload(requestId)
+
+	return &model.SigningKeyResponse{
+		Success:    true,
+		Message:    "Signing key promoted successfully",
+		StatusCode: 200,
+	}, nil
+}
+
+// RetireSigningKey schedules the retirement of the key. Tokens signed with a retired key are no longer accepted
+func (tokenService *TokenService) RetireSigningKey(requestId string, kid string, request model.RetireSigningKeyRequest) (*model.SigningKeyResponse, *model.ErrorResponse) {
+	tokenService.keyRing.mutex.RLock()
+	currentKid := tokenService.keyRing.currentKid
+	tokenService.keyRing.mutex.RUnlock()
+
+	if kid == currentKid {
+		return nil, utils.BadRequestErrorResponse("Current signing key can't be retired. Promote another key first")
+	}
+
+	retireAt := request.RetireAt
+
+	if retireAt <= 0 {
+		retireAt = time.Now().Unix()
+	}
+
+	if err := tokenDao.UpdateSigningKeyRetireAt(requestId, kid, retireAt); err != nil {
+		return nil, err
+	}
+
+	tokenService.keyRing.reload(requestId)
+
+	return &model.SigningKeyResponse{
+		Success:    true,
+		Message:    "Signing key retirement scheduled successfully",
+		StatusCode: 200,
+	}, nil
+}
@@ -0,0 +1,318 @@
+package token_service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	tokenDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/token"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+type keyRingEntry struct {
+	key       *SigningKey
+	isCurrent bool
+	retireAt  *int64
+	createdAt int64
+}
+
+// KeyRing holds every signing key known to the service. Tokens are signed with the current key and verified with
+// the key referenced by their kid header as long as that key is not retired
+type KeyRing struct {
+	mutex              sync.RWMutex
+	entries            map[string]*keyRingEntry
+	currentKid         string
+	envKey             *SigningKey
+	previousSecretKeys map[string]*SigningKey
+}
+
+func newKeyRing() *KeyRing {
+	envKey := loadSigningKeyFromEnv()
+
+	ring := &KeyRing{
+		entries: map[string]*keyRingEntry{
+			envKey.Kid: {key: envKey, isCurrent: true},
+		},
+		currentKid:         envKey.Kid,
+		envKey:             envKey,
+		previousSecretKeys: loadPreviousSecretKeysFromEnv(),
+	}
+
+	ring.checkEnvSecret("")
+	ring.reload("")
+
+	return ring
+}
+
+// reload rebuilds the key ring from the database. The key configured through the environment is seeded into the
+// database on first start so that it can be rotated and retired like every other key
+func (ring *KeyRing) reload(requestId string) {
+	dbKeys, err := tokenDao.FetchSigningKeys(requestId)
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error reloading signing keys, keeping existing key ring",
+				zap.String(constants.RequestIdLogKey, requestId),
+			)
+		}
+		return
+	}
+
+	if !containsKid(dbKeys, ring.envKey.Kid) {
+		envKeyEntity := entity.SigningKey{
+			Kid:               ring.envKey.Kid,
+			Algorithm:         ring.envKey.Method.Alg(),
+			PrivateKeyPath:    ring.envKey.privateKeyPath,
+			SecretFingerprint: ring.envKey.secretFingerprint(),
+			IsCurrent:         !hasCurrentKey(dbKeys),
+		}
+
+		if saveError := tokenDao.SaveSigningKey(requestId, &envKeyEntity); saveError == nil {
+			dbKeys = append(dbKeys, envKeyEntity)
+		}
+	}
+
+	ring.mutex.RLock()
+	previousEntries := ring.entries
+	ring.mutex.RUnlock()
+
+	entries := make(map[string]*keyRingEntry, len(dbKeys))
+	currentKid := ""
+	now := time.Now().Unix()
+
+	for _, dbKey := range dbKeys {
+		var signingKey *SigningKey
+
+		if dbKey.Kid == ring.envKey.Kid {
+			signingKey = ring.envKey
+		} else if previous, found := previousEntries[dbKey.Kid]; found {
+			signingKey = previous.key
+		} else if dbKey.Algorithm == jwt.SigningMethodHS256.Alg() {
+			previousKey, found := ring.previousSecretKeys[dbKey.Kid]
+
+			if !found || (dbKey.SecretFingerprint != "" && dbKey.SecretFingerprint != previousKey.secretFingerprint()) {
+				if logger.IsErrorEnabled() {
+					logger.Error("Secret of HS256 signing key missing or wrong in JWT_PREVIOUS_SECRET_KEYS",
+						zap.String(constants.RequestIdLogKey, requestId),
+						zap.String("kid", dbKey.Kid),
+					)
+				}
+				continue
+			}
+
+			signingKey = previousKey
+		} else {
+			loadedKey, loadError := LoadSigningKeyFromPEM(dbKey.Kid, dbKey.Algorithm, dbKey.PrivateKeyPath)
+
+			if loadError != nil {
+				if logger.IsErrorEnabled() {
+					logger.Error("Error loading signing key",
+						zap.String(constants.RequestIdLogKey, requestId),
+						zap.String("kid", dbKey.Kid),
+						zap.Error(loadError),
+					)
+				}
+				continue
+			}
+
+			signingKey = loadedKey
+		}
+
+		entries[dbKey.Kid] = &keyRingEntry{
+			key:       signingKey,
+			isCurrent: dbKey.IsCurrent,
+			retireAt:  dbKey.RetireAt,
+			createdAt: dbKey.CreatedAt,
+		}
+
+		if dbKey.IsCurrent && !isRetired(dbKey.RetireAt, now) {
+			currentKid = dbKey.Kid
+		}
+	}
+
+	if currentKid == "" {
+		if logger.IsWarnEnabled() {
+			logger.Warn("No current signing key found in DB, falling back to the configured key",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("kid", ring.envKey.Kid),
+			)
+		}
+
+		currentKid = ring.envKey.Kid
+
+		if _, found := entries[currentKid]; !found {
+			entries[currentKid] = &keyRingEntry{key: ring.envKey, isCurrent: true}
+		}
+	}
+
+	ring.mutex.Lock()
+	ring.entries = entries
+	ring.currentKid = currentKid
+	ring.mutex.Unlock()
+
+	if logger.IsDebugEnabled() {
+		logger.Debug("Signing key ring loaded",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("current_kid", currentKid),
+			zap.Int("keys", len(entries)),
+		)
+	}
+}
+
+// checkEnvSecret refuses to start when JWT_SECRET_KEY was changed under a kid which is already in use, since every
+// token issued with the previous secret would silently stop verifying. The secret is rotated by configuring a new
+// JWT_KEY_ID and moving the previous secret into JWT_PREVIOUS_SECRET_KEYS
+func (ring *KeyRing) checkEnvSecret(requestId string) {
+	fingerprint := ring.envKey.secretFingerprint()
+
+	if fingerprint == "" {
+		return
+	}
+
+	dbKeys, err := tokenDao.FetchSigningKeys(requestId)
+
+	if err != nil {
+		return
+	}
+
+	for _, dbKey := range dbKeys {
+		if dbKey.Kid != ring.envKey.Kid {
+			continue
+		}
+
+		if dbKey.SecretFingerprint == "" {
+			// the key was seeded before fingerprints were recorded
+			_ = tokenDao.UpdateSigningKeyFingerprint(requestId, dbKey.Kid, fingerprint)
+		} else if dbKey.SecretFingerprint != fingerprint {
+			panic(fmt.Sprintf("JWT_SECRET_KEY does not match the secret of signing key %s. Configure a new JWT_KEY_ID "+
+				"to rotate the secret and keep the previous secret in JWT_PREVIOUS_SECRET_KEYS", dbKey.Kid))
+		}
+	}
+}
+
+func (ring *KeyRing) currentKey() *SigningKey {
+	ring.mutex.RLock()
+	defer ring.mutex.RUnlock()
+
+	return ring.entries[ring.currentKid].key
+}
+
+// verificationKey returns the key which should be used to verify a token with the provided kid. Tokens issued
+// before kid headers were introduced are verified with the configured key
+func (ring *KeyRing) verificationKey(kid string) (*SigningKey, error) {
+	if kid == "" {
+		kid = ring.envKey.Kid
+	}
+
+	ring.mutex.RLock()
+	defer ring.mutex.RUnlock()
+
+	entry, found := ring.entries[kid]
+
+	if !found {
+		return nil, fmt.Errorf("unknown key id: %s", kid)
+	}
+
+	if isRetired(entry.retireAt, time.Now().Unix()) {
+		return nil, fmt.Errorf("key %s is retired", kid)
+	}
+
+	return entry.key, nil
+}
+
+func (ring *KeyRing) validMethods() []string {
+	return []string{
+		jwt.SigningMethodHS256.Alg(),
+		jwt.SigningMethodRS256.Alg(),
+		jwt.SigningMethodES256.Alg(),
+	}
+}
+
+// publicKeys returns the JWKs of every asymmetric key which is not retired yet
+func (ring *KeyRing) publicKeys() []model.JWK {
+	ring.mutex.RLock()
+	defer ring.mutex.RUnlock()
+
+	keys := make([]model.JWK, 0, len(ring.entries))
+	now := time.Now().Unix()
+
+	for _, entry := range ring.entries {
+		if isRetired(entry.retireAt, now) {
+			continue
+		}
+
+		if jwk, ok := entry.key.JWK(); ok {
+			keys = append(keys, *jwk)
+		}
+	}
+
+	return keys
+}
+
+func (ring *KeyRing) describe() []model.SigningKeyInfo {
+	ring.mutex.RLock()
+	defer ring.mutex.RUnlock()
+
+	keys := make([]model.SigningKeyInfo, 0, len(ring.entries))
+	now := time.Now().Unix()
+
+	for kid, entry := range ring.entries {
+		status := "active"
+
+		if isRetired(entry.retireAt, now) {
+			status = "retired"
+		}
+
+		keys = append(keys, model.SigningKeyInfo{
+			Kid:       kid,
+			Algorithm: entry.key.Method.Alg(),
+			IsCurrent: kid == ring.currentKid,
+			Status:    status,
+			RetireAt:  entry.retireAt,
+			CreatedAt: entry.createdAt,
+		})
+	}
+
+	return keys
+}
+
+// InitKeyRingRefresher periodically reloads the key ring so that key changes made through another instance are
+// picked up without a restart
+func InitKeyRingRefresher() {
+	go func() {
+		refreshFrequency := utils.GetEnvDurationSeconds("JWT_KEY_RING_REFRESH_INTERVAL_SECONDS", 1*time.Minute)
+
+		for {
+			time.Sleep(refreshFrequency)
+			GetInstance().keyRing.reload("")
+		}
+	}()
+}
+
+func containsKid(keys []entity.SigningKey, kid string) bool {
+	for _, key := range keys {
+		if key.Kid == kid {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCurrentKey(keys []entity.SigningKey) bool {
+	for _, key := range keys {
+		if key.IsCurrent {
+			return true
+		}
+	}
+	return false
+}
+
+func isRetired(retireAt *int64, now int64) bool {
+	return retireAt != nil && *retireAt <= now
+}
@@ -0,0 +1,110 @@
+package token_service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+const mfaChallengeTokenType = "mfa_challenge"
+
+// GenerateMfaChallengeToken generates the short-lived token proving that the user passed the first login factor.
+// It is signed with its own secret so that it can never be accepted as an access token
+func (tokenService *TokenService) GenerateMfaChallengeToken(
+	requestId string,
+	userId string,
+	loginMethod constants.SessionLoginMethod,
+) (string, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Generating MFA challenge token",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", userId),
+		)
+	}
+
+	claims := jwt.MapClaims{
+		"typ": mfaChallengeTokenType,
+		"jti": generateId(),
+		"uid": userId,
+		"lm":  string(loginMethod),
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Unix() + tokenService.mfaChallengeValidity,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	challengeToken, err := token.SignedString(tokenService.mfaChallengeSecretKey)
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error while generating MFA challenge token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return "", utils.InternalServerErrorResponse()
+	}
+
+	return challengeToken, nil
+}
+
+// ValidateMfaChallengeToken validates the MFA challenge token and returns the id of the user it was issued to along
+// with the method used for the first login factor
+func (tokenService *TokenService) ValidateMfaChallengeToken(
+	requestId string,
+	challengeToken string,
+) (string, constants.SessionLoginMethod, *model.ErrorResponse) {
+	parsedToken, err := jwt.Parse(challengeToken, func(token *jwt.Token) (any, error) {
+		return tokenService.mfaChallengeSecretKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error validating MFA challenge token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return "", "", utils.GetErrorResponse("Invalid or expired MFA token", 401)
+	}
+
+	claims, _ := parsedToken.Claims.(jwt.MapClaims)
+	tokenType, _ := claims["typ"].(string)
+	uId, _ := claims["uid"].(string)
+	loginMethod, _ := claims["lm"].(string)
+
+	if tokenType != mfaChallengeTokenType || uId == "" {
+		return "", "", utils.GetErrorResponse("Invalid or expired MFA token", 401)
+	}
+
+	return uId, constants.SessionLoginMethod(loginMethod), nil
+}
+
+// getMfaChallengeSecretKey returns the configured secret. A random one is used when it is not configured, which
+// only works when a single instance of the service is running
+func getMfaChallengeSecretKey() []byte {
+	secret := utils.GetEnvVariable("MFA_CHALLENGE_SECRET_KEY", "")
+
+	if secret != "" {
+		return []byte(secret)
+	}
+
+	if logger.IsWarnEnabled() {
+		logger.Warn("MFA_CHALLENGE_SECRET_KEY not found, using a random secret")
+	}
+
+	randomSecret := make([]byte, 32)
+
+	if _, err := rand.Read(randomSecret); err != nil {
+		panic(fmt.Sprintf("Error generating MFA challenge secret: %v", err))
+	}
+
+	return randomSecret
+}
@@ -0,0 +1,104 @@
+package token_service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+const oAuthStateTokenType = "oauth_state"
+
+// GenerateOAuthStateToken generates the signed state sent to the OAuth provider with the authorization request. It
+// references the server side record of the request by stateId and is bound to the provider
+func (tokenService *TokenService) GenerateOAuthStateToken(
+	requestId string,
+	stateId string,
+	provider constants.OAuthProvider,
+	expiresAt int64,
+) (string, *model.ErrorResponse) {
+	claims := jwt.MapClaims{
+		"typ": oAuthStateTokenType,
+		"jti": stateId,
+		"prv": string(provider),
+		"iat": time.Now().Unix(),
+		"exp": expiresAt,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	stateToken, err := token.SignedString(tokenService.oAuthStateSecretKey)
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error while generating OAuth state token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return "", utils.InternalServerErrorResponse()
+	}
+
+	return stateToken, nil
+}
+
+// ValidateOAuthStateToken validates the signature and expiry of the state and that it was issued for the provider.
+// Returns the id of the server side record of the authorization request
+func (tokenService *TokenService) ValidateOAuthStateToken(
+	requestId string,
+	stateToken string,
+	provider constants.OAuthProvider,
+) (string, *model.ErrorResponse) {
+	parsedToken, err := jwt.Parse(stateToken, func(token *jwt.Token) (any, error) {
+		return tokenService.oAuthStateSecretKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error validating OAuth state token",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(err),
+			)
+		}
+		return "", utils.GetErrorResponse("Invalid or expired OAuth state", 400)
+	}
+
+	claims, _ := parsedToken.Claims.(jwt.MapClaims)
+	tokenType, _ := claims["typ"].(string)
+	stateId, _ := claims["jti"].(string)
+	stateProvider, _ := claims["prv"].(string)
+
+	if tokenType != oAuthStateTokenType || stateId == "" || stateProvider != string(provider) {
+		return "", utils.GetErrorResponse("Invalid or expired OAuth state", 400)
+	}
+
+	return stateId, nil
+}
+
+// getOAuthStateSecretKey returns the configured secret. A random one is used when it is not configured, which
+// only works when a single instance of the service is running
+func getOAuthStateSecretKey() []byte {
+	secret := utils.GetEnvVariable("OAUTH_STATE_SECRET_KEY", "")
+
+	if secret != "" {
+		return []byte(secret)
+	}
+
+	if logger.IsWarnEnabled() {
+		logger.Warn("OAUTH_STATE_SECRET_KEY not found, using a random secret")
+	}
+
+	randomSecret := make([]byte, 32)
+
+	if _, err := rand.Read(randomSecret); err != nil {
+		panic(fmt.Sprintf("Error generating OAuth state secret: %v", err))
+	}
+
+	return randomSecret
+}
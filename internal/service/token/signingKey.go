@@ -0,0 +1,180 @@
+package token_service
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey holds the key material used to sign and verify JWT tokens along with its key id
+type SigningKey struct {
+	Kid            string
+	Method         jwt.SigningMethod
+	signKey        interface{}
+	verifyKey      interface{}
+	privateKeyPath string
+}
+
+// NewHMACSigningKey creates a symmetric HS256 signing key from the shared secret
+func NewHMACSigningKey(kid string, secret []byte) *SigningKey {
+	return &SigningKey{
+		Kid:       kid,
+		Method:    jwt.SigningMethodHS256,
+		signKey:   secret,
+		verifyKey: secret,
+	}
+}
+
+// LoadSigningKeyFromPEM loads an RSA (RS256) or ECDSA (ES256) private key from the PEM file
+func LoadSigningKeyFromPEM(kid string, algorithm string, privateKeyPath string) (*SigningKey, error) {
+	pemBytes, err := os.ReadFile(privateKeyPath)
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading private key file %s: %w", privateKeyPath, err)
+	}
+
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+
+		if err != nil {
+			return nil, fmt.Errorf("error parsing RSA private key: %w", err)
+		}
+
+		return &SigningKey{
+			Kid:            kid,
+			Method:         jwt.SigningMethodRS256,
+			signKey:        privateKey,
+			verifyKey:      &privateKey.PublicKey,
+			privateKeyPath: privateKeyPath,
+		}, nil
+
+	case jwt.SigningMethodES256.Alg():
+		privateKey, err := jwt.ParseECPrivateKeyFromPEM(pemBytes)
+
+		if err != nil {
+			return nil, fmt.Errorf("error parsing ECDSA private key: %w", err)
+		}
+
+		if privateKey.Curve.Params().Name != "P-256" {
+			return nil, fmt.Errorf("ES256 requires a P-256 key, found %s", privateKey.Curve.Params().Name)
+		}
+
+		return &SigningKey{
+			Kid:            kid,
+			Method:         jwt.SigningMethodES256,
+			signKey:        privateKey,
+			verifyKey:      &privateKey.PublicKey,
+			privateKeyPath: privateKeyPath,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+}
+
+// JWK returns the public part of the key in JWK format. Symmetric keys are never published
+func (key *SigningKey) JWK() (*model.JWK, bool) {
+	switch publicKey := key.verifyKey.(type) {
+	case *rsa.PublicKey:
+		return &model.JWK{
+			Kty: "RSA",
+			Kid: key.Kid,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		}, true
+
+	case *ecdsa.PublicKey:
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		return &model.JWK{
+			Kty: "EC",
+			Kid: key.Kid,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+			Crv: publicKey.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, size))),
+			Y:   base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, size))),
+		}, true
+	}
+
+	return nil, false
+}
+
+// secretFingerprint identifies the HS256 secret without revealing it, so that a secret swapped under an existing kid
+// can be detected. Asymmetric keys are identified by their private key path and have no fingerprint
+func (key *SigningKey) secretFingerprint() string {
+	secret, ok := key.signKey.([]byte)
+
+	if !ok {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("jwt-signing-key-fingerprint"))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (key *SigningKey) sign(token *jwt.Token) (string, error) {
+	token.Header["kid"] = key.Kid
+	return token.SignedString(key.signKey)
+}
+
+func loadSigningKeyFromEnv() *SigningKey {
+	kid := utils.GetEnvVariable("JWT_KEY_ID", "default")
+	algorithm := utils.GetEnvVariable("JWT_SIGNING_ALGORITHM", jwt.SigningMethodHS256.Alg())
+
+	if algorithm == jwt.SigningMethodHS256.Alg() {
+		return NewHMACSigningKey(kid, []byte(getJWTSecretKey()))
+	}
+
+	privateKeyPath := utils.GetEnvVariable("JWT_PRIVATE_KEY_PATH", "")
+
+	if privateKeyPath == "" {
+		panic("JWT_PRIVATE_KEY_PATH not found")
+	}
+
+	signingKey, err := LoadSigningKeyFromPEM(kid, algorithm, privateKeyPath)
+
+	if err != nil {
+		panic(fmt.Sprintf("Error loading JWT signing key: %v", err))
+	}
+
+	return signingKey
+}
+
+// loadPreviousSecretKeysFromEnv loads the HS256 secrets which were rotated out of JWT_SECRET_KEY. They are only used
+// to verify tokens issued before the rotation, until their kid is retired
+func loadPreviousSecretKeysFromEnv() map[string]*SigningKey {
+	previousKeys := make(map[string]*SigningKey)
+
+	for _, pair := range strings.Split(utils.GetEnvVariable("JWT_PREVIOUS_SECRET_KEYS", ""), ",") {
+		pair = strings.TrimSpace(pair)
+
+		if pair == "" {
+			continue
+		}
+
+		kid, secret, found := strings.Cut(pair, ":")
+
+		if !found || kid == "" || secret == "" {
+			panic("JWT_PREVIOUS_SECRET_KEYS must be a comma separated list of kid:secret pairs")
+		}
+
+		previousKeys[kid] = NewHMACSigningKey(kid, []byte(secret))
+	}
+
+	return previousKeys
+}
@@ -4,63 +4,93 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	authDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/auth"
+	sessionDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/session"
+	tokenDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/token"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
 	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
 	"github.com/akgarg0472/urlshortener-auth-service/model"
 	"github.com/akgarg0472/urlshortener-auth-service/utils"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 var (
 	instance *TokenService
+	once     sync.Once
 )
 
 type TokenService struct {
-	jwtSecretKey            []byte
-	jwtIssuer               string
-	jwtValidity             int64
-	forgotPasswordSecretKey []byte
-	forgotPasswordValidity  int64
+	keyRing               *KeyRing
+	jwtIssuer             string
+	jwtClientId           string
+	introspectionClients  map[string]string
+	jwtValidity           int64
+	refreshTokenValidity  int64
+	mfaChallengeSecretKey []byte
+	mfaChallengeValidity  int64
+	emailVerifiedClaim    bool
+	oAuthStateSecretKey   []byte
 }
 
 func GetInstance() *TokenService {
-	if instance == nil {
+	once.Do(func() {
 		instance = &TokenService{
-			jwtSecretKey:            []byte(getJWTSecretKey()),
-			jwtIssuer:               getJWTIssuer(),
-			jwtValidity:             getJWTValidityDurationInSeconds(),
-			forgotPasswordSecretKey: []byte(getForgotPasswordSecretKey()),
-			forgotPasswordValidity:  getForgotPasswordValidityDurationInSeconds(),
+			keyRing:               newKeyRing(),
+			jwtIssuer:             getJWTIssuer(),
+			jwtClientId:           getJWTClientId(),
+			introspectionClients:  getIntrospectionClients(),
+			jwtValidity:           getJWTValidityDurationInSeconds(),
+			refreshTokenValidity:  getRefreshTokenValidityDurationInSeconds(),
+			mfaChallengeSecretKey: getMfaChallengeSecretKey(),
+			mfaChallengeValidity:  int64(utils.GetEnvDurationSeconds("MFA_CHALLENGE_EXPIRY", 5*time.Minute).Seconds()),
+			emailVerifiedClaim:    isEmailVerifiedClaimEnabled(),
+			oAuthStateSecretKey:   getOAuthStateSecretKey(),
 		}
-	}
+	})
 
 	return instance
 }
 
-// GenerateJwtToken generates the JWT token and stores it in the map
-func (tokenService *TokenService) GenerateJwtToken(requestId string, user model.User) (string, *model.ErrorResponse) {
+// GenerateJwtToken generates the JWT token with a unique jti so that it can be revoked later. The sessionId is
+// embedded as sid claim so that the token stops working once its session is revoked
+func (tokenService *TokenService) GenerateJwtToken(requestId string, user model.User, sessionId string) (string, *model.ErrorResponse) {
 	if logger.IsInfoEnabled() {
 		logger.Info("Generating JWT token",
 			zap.String(constants.RequestIdLogKey, requestId),
 			zap.String("user", user.String()),
+			zap.String("sessionId", sessionId),
 		)
 	}
 
 	claims := jwt.MapClaims{
-		"iss":    tokenService.jwtIssuer,
-		"sub":    user.Email,
-		"uid":    user.Id,
-		"scopes": user.Scopes,
-		"iat":    time.Now().Unix(),
-		"exp":    time.Now().Unix() + tokenService.jwtValidity,
+		"iss":       tokenService.jwtIssuer,
+		"jti":       generateId(),
+		"sub":       user.Email,
+		"uid":       user.Id,
+		"scopes":    user.Scopes,
+		"client_id": tokenService.jwtClientId,
+		"iat":       time.Now().Unix(),
+		"exp":       time.Now().Unix() + tokenService.jwtValidity,
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if sessionId != "" {
+		claims["sid"] = sessionId
+	}
 
-	jwtTokenString, err := token.SignedString(tokenService.jwtSecretKey)
+	if tokenService.emailVerifiedClaim {
+		claims["email_verified"] = user.EmailVerified
+	}
+
+	signingKey := tokenService.keyRing.currentKey()
+	token := jwt.NewWithClaims(signingKey.Method, claims)
+
+	jwtTokenString, err := signingKey.sign(token)
 
 	if err != nil {
 		if logger.IsErrorEnabled() {
@@ -75,7 +105,7 @@ func (tokenService *TokenService) GenerateJwtToken(requestId string, user model.
 	return jwtTokenString, nil
 }
 
-// ValidateJwtToken validates the JWT token by checking if it is valid and not expired
+// ValidateJwtToken validates the JWT token by checking if it is valid, not expired and not revoked
 func (tokenService *TokenService) ValidateJwtToken(
 	requestId string,
 	jwtToken string,
@@ -89,12 +119,200 @@ func (tokenService *TokenService) ValidateJwtToken(
 		)
 	}
 
-	token, err := jwt.Parse(jwtToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("error parsing token")
+	token, claims, parseError := tokenService.parseJwtToken(requestId, jwtToken)
+
+	if parseError != nil {
+		return nil, parseError
+	}
+
+	uId, _ := claims["uid"].(string)
+
+	if strings.TrimSpace(uId) != strings.TrimSpace(userId) {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error validating token: Invalid userId",
+				zap.String(constants.RequestIdLogKey, requestId),
+			)
 		}
-		return tokenService.jwtSecretKey, nil
+		return nil, utils.BadRequestErrorResponse("JWT_TOKEN_USER_MISMATCH")
+	}
+
+	if revocationError := tokenService.checkJwtTokenRevocation(requestId, claims); revocationError != nil {
+		return nil, revocationError
+	}
+
+	return &model.ValidateTokenResponse{
+		UserId:     uId,
+		Expiration: claims["exp"].(float64),
+		Token:      token.Raw,
+		Success:    userId == uId,
+	}, nil
+}
+
+// AuthenticateJwtToken validates the JWT token presented by a client and returns its claims
+func (tokenService *TokenService) AuthenticateJwtToken(requestId string, jwtToken string) (*model.AuthClaims, *model.ErrorResponse) {
+	_, claims, parseError := tokenService.parseJwtToken(requestId, jwtToken)
+
+	if parseError != nil {
+		return nil, parseError
+	}
+
+	if revocationError := tokenService.checkJwtTokenRevocation(requestId, claims); revocationError != nil {
+		return nil, revocationError
+	}
+
+	return mapClaimsToAuthClaims(claims), nil
+}
+
+// RevokeJwtToken adds the JWT token to the denylist until it expires. Invalid and expired tokens are ignored
+func (tokenService *TokenService) RevokeJwtToken(requestId string, jwtToken string, userId string) *model.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Revoking JWT token",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", userId),
+		)
+	}
+
+	_, claims, parseError := tokenService.parseJwtToken(requestId, jwtToken)
+
+	if parseError != nil {
+		return nil
+	}
+
+	uId, _ := claims["uid"].(string)
+	jti, _ := claims["jti"].(string)
+	sid, _ := claims["sid"].(string)
+	exp, _ := claims["exp"].(float64)
+
+	if uId != userId {
+		return utils.GetErrorResponse("Token does not belong to user", 403)
+	}
+
+	if sid != "" {
+		if err := tokenService.RevokeSession(requestId, sid); err != nil {
+			return err
+		}
+	}
+
+	if jti == "" {
+		return nil
+	}
+
+	return tokenDao.SaveRevokedToken(requestId, &entity.RevokedToken{
+		Jti:       jti,
+		UserId:    uId,
+		ExpiresAt: int64(exp),
 	})
+}
+
+// RevokeAllUserTokens invalidates every access token issued to the user till now along with all of its refresh tokens
+func (tokenService *TokenService) RevokeAllUserTokens(requestId string, userId string) *model.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Revoking all tokens of user",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", userId),
+		)
+	}
+
+	if err := authDao.SetTokensRevokedAt(requestId, userId); err != nil {
+		return err
+	}
+
+	if err := sessionDao.RevokeUserSessions(requestId, userId, ""); err != nil {
+		return err
+	}
+
+	return tokenDao.RevokeUserRefreshTokens(requestId, userId, "")
+}
+
+// RevokeOtherUserSessions ends every session of the user except the current one along with their refresh tokens
+func (tokenService *TokenService) RevokeOtherUserSessions(requestId string, userId string, currentSessionId string) *model.ErrorResponse {
+	if logger.IsInfoEnabled() {
+		logger.Info("Revoking other sessions of user",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", userId),
+			zap.String("sessionId", currentSessionId),
+		)
+	}
+
+	if err := sessionDao.RevokeUserSessions(requestId, userId, currentSessionId); err != nil {
+		return err
+	}
+
+	// refresh token family of a session shares the id of the session
+	return tokenDao.RevokeUserRefreshTokens(requestId, userId, currentSessionId)
+}
+
+// RevokeSession ends the session by revoking it along with its refresh token family. Access tokens issued for the
+// session are rejected from now on as they carry its id in the sid claim
+func (tokenService *TokenService) RevokeSession(requestId string, sessionId string) *model.ErrorResponse {
+	if err := sessionDao.RevokeSession(requestId, sessionId); err != nil {
+		return err
+	}
+
+	return tokenDao.RevokeRefreshTokenFamily(requestId, sessionId)
+}
+
+// RevokeRefreshToken revokes the family of the refresh token if it belongs to the user
+func (tokenService *TokenService) RevokeRefreshToken(requestId string, rawToken string, userId string) *model.ErrorResponse {
+	refreshToken, err := tokenDao.GetRefreshTokenByHash(requestId, utils.HashToken(rawToken))
+
+	if err != nil {
+		if err.ErrorCode == 401 {
+			return nil
+		}
+		return err
+	}
+
+	if refreshToken.UserId != userId {
+		return utils.GetErrorResponse("Token does not belong to user", 403)
+	}
+
+	// refresh token family of a session shares the id of the session
+	return tokenService.RevokeSession(requestId, refreshToken.FamilyId)
+}
+
+// GetJWKS returns the public keys which can be used to verify the issued JWT tokens
+func (tokenService *TokenService) GetJWKS() *model.JWKSResponse {
+	return &model.JWKSResponse{
+		Keys: tokenService.keyRing.publicKeys(),
+	}
+}
+
+// InitRevokedTokenPurger periodically removes the denylist entries of already expired tokens
+func InitRevokedTokenPurger() {
+	go func() {
+		purgeFrequency := utils.GetEnvDurationSeconds("REVOKED_TOKEN_PURGE_INTERVAL_SECONDS", 1*time.Hour)
+
+		for {
+			time.Sleep(purgeFrequency)
+
+			deleted, err := tokenDao.DeleteExpiredRevokedTokens("")
+
+			if err == nil && logger.IsDebugEnabled() {
+				logger.Debug("Purged expired revoked tokens",
+					zap.Int64("count", deleted),
+				)
+			}
+		}
+	}()
+}
+
+func (tokenService *TokenService) parseJwtToken(requestId string, jwtToken string) (*jwt.Token, jwt.MapClaims, *model.ErrorResponse) {
+	token, err := jwt.Parse(jwtToken, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		signingKey, err := tokenService.keyRing.verificationKey(kid)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if token.Method.Alg() != signingKey.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+		}
+
+		return signingKey.verifyKey, nil
+	}, jwt.WithValidMethods(tokenService.keyRing.validMethods()))
 
 	if err != nil {
 		if logger.IsErrorEnabled() {
@@ -107,55 +325,107 @@ func (tokenService *TokenService) ValidateJwtToken(
 		if token != nil {
 			claims, isMapClaims := token.Claims.(jwt.MapClaims)
 
-			if isMapClaims {
-				return nil, utils.ParseAndGenerateJwtErrorResponse(claims)
+			if isMapClaims && claims["exp"] != nil {
+				return nil, nil, utils.ParseAndGenerateJwtErrorResponse(claims)
 			}
 		}
 
-		return nil, utils.BadRequestErrorResponse("JWT_TOKEN_INVALID")
+		return nil, nil, utils.BadRequestErrorResponse("JWT_TOKEN_INVALID")
 	}
 
 	claims, _ := token.Claims.(jwt.MapClaims)
-	uId := claims["uid"].(string)
 
-	if strings.TrimSpace(uId) != strings.TrimSpace(userId) {
-		if logger.IsErrorEnabled() {
-			logger.Error("Error validating token: Invalid userId",
+	return token, claims, nil
+}
+
+// checkJwtTokenRevocation rejects tokens present in the denylist, belonging to a revoked session or issued before the
+// user logged out everywhere
+func (tokenService *TokenService) checkJwtTokenRevocation(requestId string, claims jwt.MapClaims) *model.ErrorResponse {
+	jti, _ := claims["jti"].(string)
+
+	if jti != "" {
+		revoked, err := tokenDao.IsTokenRevoked(requestId, jti)
+
+		if err != nil {
+			return err
+		}
+
+		if revoked {
+			if logger.IsInfoEnabled() {
+				logger.Info("Revoked token presented",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.String("jti", jti),
+				)
+			}
+			return utils.BadRequestErrorResponse("JWT_TOKEN_REVOKED")
+		}
+	}
+
+	sid, _ := claims["sid"].(string)
+
+	if sid != "" {
+		session, err := sessionDao.GetSessionById(requestId, sid)
+
+		if err != nil {
+			if err.ErrorCode == 404 {
+				return utils.BadRequestErrorResponse("JWT_TOKEN_INVALID")
+			}
+			return err
+		}
+
+		if session.RevokedAt != nil {
+			if logger.IsInfoEnabled() {
+				logger.Info("Token of revoked session presented",
+					zap.String(constants.RequestIdLogKey, requestId),
+					zap.String("sessionId", sid),
+				)
+			}
+			return utils.BadRequestErrorResponse("JWT_TOKEN_REVOKED")
+		}
+	}
+
+	uId, _ := claims["uid"].(string)
+	iat, _ := claims["iat"].(float64)
+
+	user, err := authDao.GetUserById(requestId, uId)
+
+	if err != nil {
+		if err.ErrorCode == 404 {
+			return utils.BadRequestErrorResponse("JWT_TOKEN_INVALID")
+		}
+		return err
+	}
+
+	// iat has a precision of one second, so only the tokens issued in an earlier second are rejected here. Tokens issued in
+	// the same second before the revocation are still rejected through their revoked session
+	if user.TokensRevokedAt > 0 && int64(iat) < user.TokensRevokedAt/1000 {
+		if logger.IsInfoEnabled() {
+			logger.Info("Token issued before user revoked all tokens",
 				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("userId", uId),
 			)
 		}
-		return nil, utils.BadRequestErrorResponse("Passwords mismatch")
+		return utils.BadRequestErrorResponse("JWT_TOKEN_REVOKED")
 	}
 
-	return &model.ValidateTokenResponse{
-		UserId:     uId,
-		Expiration: claims["exp"].(float64),
-		Token:      token.Raw,
-		Success:    userId == uId,
-	}, nil
+	return nil
 }
 
-func (tokenService *TokenService) GenerateForgotPasswordToken(requestId string, user model.User) (string, *model.ErrorResponse) {
+// GenerateRefreshToken generates an opaque refresh token for the user and stores its digest in the database.
+// An empty familyId starts a new token family
+func (tokenService *TokenService) GenerateRefreshToken(requestId string, userId string, familyId string) (string, *model.ErrorResponse) {
 	if logger.IsInfoEnabled() {
-		logger.Info("Generating forgot password token",
+		logger.Info("Generating refresh token",
 			zap.String(constants.RequestIdLogKey, requestId),
-			zap.String("user", user.String()),
+			zap.String("userId", userId),
 		)
 	}
 
-	claims := jwt.MapClaims{
-		"sub": user.Email,
-		"iat": time.Now().Unix(),
-		"exp": time.Now().Unix() + tokenService.forgotPasswordValidity,
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	forgotPasswordToken, err := token.SignedString(tokenService.forgotPasswordSecretKey)
+	rawToken, err := utils.GenerateSecureRandomToken(32)
 
 	if err != nil {
 		if logger.IsErrorEnabled() {
-			logger.Error("Error while generating forgot password token",
+			logger.Error("Error while generating refresh token",
 				zap.String(constants.RequestIdLogKey, requestId),
 				zap.Error(err),
 			)
@@ -163,44 +433,99 @@ func (tokenService *TokenService) GenerateForgotPasswordToken(requestId string,
 		return "", utils.InternalServerErrorResponse()
 	}
 
-	return forgotPasswordToken, nil
+	if familyId == "" {
+		familyId = generateId()
+	}
+
+	refreshToken := &entity.RefreshToken{
+		Id:        generateId(),
+		UserId:    userId,
+		FamilyId:  familyId,
+		TokenHash: utils.HashToken(rawToken),
+		ExpiresAt: time.Now().Unix() + tokenService.refreshTokenValidity,
+	}
+
+	if saveError := tokenDao.SaveRefreshToken(requestId, refreshToken); saveError != nil {
+		return "", saveError
+	}
+
+	return rawToken, nil
 }
 
-func (tokenService *TokenService) ValidateForgotPasswordToken(requestId string, forgotPasswordToken string) *model.ErrorResponse {
-	if logger.IsDebugEnabled() {
-		logger.Debug("Validating Forgot Password token",
+// RotateRefreshToken consumes the provided refresh token and issues a new one in the same family. Presenting a
+// refresh token which is already used revokes the whole family as the token is considered to be stolen
+func (tokenService *TokenService) RotateRefreshToken(requestId string, rawToken string) (*entity.RefreshToken, string, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info("Rotating refresh token",
 			zap.String(constants.RequestIdLogKey, requestId),
-			zap.String("forgotPasswordToken", forgotPasswordToken),
 		)
 	}
 
-	parsedToken, err := jwt.Parse(forgotPasswordToken, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("error parsing token")
-		}
-		return tokenService.forgotPasswordSecretKey, nil
-	})
+	refreshToken, err := tokenDao.GetRefreshTokenByHash(requestId, utils.HashToken(rawToken))
 
 	if err != nil {
-		if logger.IsErrorEnabled() {
-			logger.Error("Error validating token",
+		return nil, "", err
+	}
+
+	if refreshToken.RevokedAt != nil {
+		if logger.IsInfoEnabled() {
+			logger.Info("Revoked refresh token presented",
 				zap.String(constants.RequestIdLogKey, requestId),
-				zap.Error(err),
+				zap.String("familyId", refreshToken.FamilyId),
 			)
 		}
+		return nil, "", utils.GetErrorResponse("Invalid refresh token", 401)
+	}
 
-		if parsedToken != nil {
-			claims, isMapClaims := parsedToken.Claims.(jwt.MapClaims)
+	if refreshToken.UsedAt != nil {
+		return nil, "", tokenService.handleRefreshTokenReuse(requestId, refreshToken)
+	}
 
-			if isMapClaims {
-				return utils.ParseAndGenerateJwtErrorResponse(claims)
-			}
+	if time.Now().Unix() > refreshToken.ExpiresAt {
+		if logger.IsInfoEnabled() {
+			logger.Info("Expired refresh token presented",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("familyId", refreshToken.FamilyId),
+			)
 		}
+		return nil, "", utils.GetErrorResponse("Refresh token expired", 401)
+	}
+
+	marked, err := tokenDao.MarkRefreshTokenUsed(requestId, refreshToken.Id)
+
+	if err != nil {
+		return nil, "", err
+	}
 
-		return utils.BadRequestErrorResponse("JWT_TOKEN_INVALID")
+	// another request consumed the same token in the meantime
+	if !marked {
+		return nil, "", tokenService.handleRefreshTokenReuse(requestId, refreshToken)
 	}
 
-	return nil
+	newRawToken, err := tokenService.GenerateRefreshToken(requestId, refreshToken.UserId, refreshToken.FamilyId)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return refreshToken, newRawToken, nil
+}
+
+func (tokenService *TokenService) handleRefreshTokenReuse(requestId string, refreshToken *entity.RefreshToken) *model.ErrorResponse {
+	if logger.IsWarnEnabled() {
+		logger.Warn("Refresh token reuse detected, revoking token family",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", refreshToken.UserId),
+			zap.String("familyId", refreshToken.FamilyId),
+			zap.String("refreshTokenId", refreshToken.Id),
+		)
+	}
+
+	if err := tokenDao.RevokeRefreshTokenFamily(requestId, refreshToken.FamilyId); err != nil {
+		return err
+	}
+
+	return utils.GetErrorResponse("Invalid refresh token", 401)
 }
 
 func getJWTSecretKey() string {
@@ -217,36 +542,76 @@ func getJWTIssuer() string {
 	return utils.GetEnvVariable("JWT_TOKEN_ISSUER", "auth-service")
 }
 
+func getJWTClientId() string {
+	return utils.GetEnvVariable("JWT_TOKEN_CLIENT_ID", "urlshortener")
+}
+
+func isEmailVerifiedClaimEnabled() bool {
+	enabled, err := strconv.ParseBool(utils.GetEnvVariable("JWT_EMAIL_VERIFIED_CLAIM_ENABLED", "false"))
+	return err == nil && enabled
+}
+
+// getIntrospectionClients parses the comma separated client_id:client_secret pairs allowed to introspect tokens
+func getIntrospectionClients() map[string]string {
+	clients := make(map[string]string)
+
+	for _, credentials := range strings.Split(utils.GetEnvVariable("INTROSPECTION_CLIENTS", ""), ",") {
+		clientId, clientSecret, found := strings.Cut(strings.TrimSpace(credentials), ":")
+
+		if !found || clientId == "" || clientSecret == "" {
+			continue
+		}
+
+		clients[clientId] = clientSecret
+	}
+
+	return clients
+}
+
 func getJWTValidityDurationInSeconds() int64 {
-	expiry := utils.GetEnvVariable("JWT_TOKEN_EXPIRY", "3600000")
+	expiry := utils.GetEnvVariable("JWT_TOKEN_EXPIRY", "900")
 
 	value, err := strconv.ParseInt(expiry, 10, 64)
 
 	if err != nil {
-		return 3600000
+		return 900
 	} else {
 		return value
 	}
 }
 
-func getForgotPasswordSecretKey() string {
-	secret := utils.GetEnvVariable("FORGOT_PASS_SECRET_KEY", "")
-
-	if secret == "" {
-		panic("FORGOT_PASS_SECRET_KEY not found")
-	}
-
-	return secret
-}
-
-func getForgotPasswordValidityDurationInSeconds() int64 {
-	expiry := utils.GetEnvVariable("FORGOT_PASS_EXPIRY", "600")
+func getRefreshTokenValidityDurationInSeconds() int64 {
+	expiry := utils.GetEnvVariable("REFRESH_TOKEN_EXPIRY", "2592000")
 
 	value, err := strconv.ParseInt(expiry, 10, 64)
 
 	if err != nil {
-		return 600
+		return 2592000
 	} else {
 		return value
 	}
 }
+
+func mapClaimsToAuthClaims(claims jwt.MapClaims) *model.AuthClaims {
+	uId, _ := claims["uid"].(string)
+	sub, _ := claims["sub"].(string)
+	scopes, _ := claims["scopes"].(string)
+	jti, _ := claims["jti"].(string)
+	sid, _ := claims["sid"].(string)
+	iat, _ := claims["iat"].(float64)
+	exp, _ := claims["exp"].(float64)
+
+	return &model.AuthClaims{
+		UserId:    uId,
+		Email:     sub,
+		Scopes:    scopes,
+		TokenId:   jti,
+		SessionId: sid,
+		IssuedAt:  int64(iat),
+		ExpiresAt: int64(exp),
+	}
+}
+
+func generateId() string {
+	return strings.ReplaceAll(uuid.New().String(), "-", "")
+}
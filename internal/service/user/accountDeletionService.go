@@ -0,0 +1,207 @@
+package user_service
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	authDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/auth"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	auditService "github.com/akgarg0472/urlshortener-auth-service/internal/service/audit"
+	kafkaService "github.com/akgarg0472/urlshortener-auth-service/internal/service/kafka"
+	notificationService "github.com/akgarg0472/urlshortener-auth-service/internal/service/notification"
+	tokenService "github.com/akgarg0472/urlshortener-auth-service/internal/service/token"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+)
+
+// number of deleted users purged per query by the purger
+const purgeBatchSize = 100
+
+// DeleteAccount Function to delete the account of the authenticated user after it proved again it owns the account.
+// The account is soft deleted and every token issued to it is revoked. It can be restored using the emailed link until
+// the grace period is over, after which the purger removes its data
+func DeleteAccount(
+	requestId string,
+	authClaims model.AuthClaims,
+	deleteRequest model.DeleteAccountRequest,
+	clientInfo model.ClientInfo,
+) (*model.DeleteAccountResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Delete Account Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+		)
+	}
+
+	user, err := authDao.GetUserById(requestId, authClaims.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := reauthenticate(requestId, authClaims, *user, deleteRequest.Password, deleteRequest.OAuth, clientInfo, "delete your account"); err != nil {
+		return nil, err
+	}
+
+	gracePeriod := getDeletionGracePeriod()
+
+	// the restore token is issued first so that a failure leaves the account untouched
+	var restoreToken string
+
+	if user.Email != "" {
+		restoreToken, err = tokenService.GetInstance().IssueActionToken(requestId, user.Id, constants.ActionTokenTypeAccountRestore, gracePeriod, nil)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	deletedAt, err := authDao.SoftDeleteUser(requestId, user.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the access tokens are already rejected as the user is deleted, this ends the sessions and refresh tokens
+	if revokeError := tokenService.GetInstance().RevokeAllUserTokens(requestId, user.Id); revokeError != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error revoking tokens of deleted user",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Int16(constants.ErrorCodeLogKey, revokeError.ErrorCode),
+				zap.Any(constants.ErrorMessageLogKey, revokeError.Message),
+			)
+		}
+	}
+
+	purgeAt := deletedAt + gracePeriod.Milliseconds()
+
+	auditService.RecordEvent(requestId, constants.AuditEventAccountDeleted, user.Id, clientInfo, nil)
+
+	message := "Your account is deleted and will be permanently removed on " + time.UnixMilli(purgeAt).UTC().Format(time.RFC1123)
+
+	if restoreToken != "" {
+		graceDays := int64(gracePeriod.Hours() / 24)
+		notificationService.SendAccountDeletedEmail(requestId, user.Email, user.Name, utils.GenerateAccountRestoreLink(restoreToken), graceDays)
+		message += ". You can restore it until then using the link we emailed you"
+	}
+
+	return &model.DeleteAccountResponse{
+		Success:    true,
+		Message:    message,
+		PurgeAt:    purgeAt,
+		StatusCode: 200,
+	}, nil
+}
+
+// RestoreAccount Function to restore the deleted account using the emailed link and return the frontend URL to redirect
+// to. The user has to log in again as its tokens were revoked by the deletion
+func RestoreAccount(requestId string, queryParams url.Values, clientInfo model.ClientInfo) (string, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Restore Account Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	restoreToken := queryParams.Get("token")
+
+	if restoreToken == "" {
+		return "", utils.BadRequestErrorResponse("Token is required")
+	}
+
+	actionToken, err := tokenService.GetInstance().ConsumeActionToken(requestId, constants.ActionTokenTypeAccountRestore, restoreToken)
+
+	if err != nil {
+		return "", err
+	}
+
+	deletedAfter := time.Now().Add(-getDeletionGracePeriod()).UnixMilli()
+
+	restored, err := authDao.RestoreUser(requestId, actionToken.UserId, deletedAfter)
+
+	if err != nil {
+		return "", err
+	}
+
+	if !restored {
+		if logger.IsInfoEnabled() {
+			logger.Info(
+				"User to restore is not deleted or already purged",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("userId", actionToken.UserId),
+			)
+		}
+		return "", utils.GetErrorResponse("Invalid or expired token", 400)
+	}
+
+	auditService.RecordEvent(requestId, constants.AuditEventAccountRestored, actionToken.UserId, clientInfo, nil)
+
+	return utils.GenerateAccountRestoredRedirectUrl(), nil
+}
+
+// InitDeletedUserPurger periodically purges the users whose deletion grace period is over and notifies the other
+// services using the user deleted event
+func InitDeletedUserPurger() {
+	go func() {
+		purgeFrequency := utils.GetEnvDurationSeconds("ACCOUNT_PURGE_INTERVAL_SECONDS", 1*time.Hour)
+
+		for {
+			time.Sleep(purgeFrequency)
+
+			purged := purgeDeletedUsers("")
+
+			if purged > 0 && logger.IsInfoEnabled() {
+				logger.Info("Purged deleted users",
+					zap.Int("count", purged),
+				)
+			}
+		}
+	}()
+}
+
+// function to purge every user whose deletion grace period is over. Returns the number of purged users
+func purgeDeletedUsers(requestId string) int {
+	deletedBefore := time.Now().Add(-getDeletionGracePeriod()).UnixMilli()
+	purgedCount := 0
+
+	for {
+		users, err := authDao.GetUsersToPurge(requestId, deletedBefore, purgeBatchSize)
+
+		if err != nil {
+			return purgedCount
+		}
+
+		for _, user := range users {
+			// the event is published before the purge is committed, so that a user whose event is lost is purged and
+			// published again by the next run. Consumers may therefore receive the event more than once
+			purged, err := authDao.PurgeUser(requestId, user.Id, deletedBefore, func(purgedAt int64) error {
+				return kafkaService.GetInstance().PublishUserDeletedEvent(requestId, model.UserDeletedEvent{
+					UserId:    user.Id,
+					DeletedAt: utils.GetInt64OrNil(user.DeletedAt),
+					PurgedAt:  purgedAt,
+				})
+			})
+
+			// a user which couldn't be purged is retried by the next run
+			if err != nil || !purged {
+				continue
+			}
+
+			purgedCount++
+
+			auditService.RecordEvent(requestId, constants.AuditEventAccountPurged, user.Id, model.ClientInfo{}, nil)
+		}
+
+		if len(users) < purgeBatchSize {
+			return purgedCount
+		}
+	}
+}
+
+func getDeletionGracePeriod() time.Duration {
+	return utils.GetEnvDurationSeconds("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour)
+}
@@ -0,0 +1,443 @@
+package user_service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	auditDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/audit"
+	authDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/auth"
+	exportDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/export"
+	identityDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/identity"
+	mfaDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/mfa"
+	passwordDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/password"
+	sessionDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/session"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	auditService "github.com/akgarg0472/urlshortener-auth-service/internal/service/audit"
+	notificationService "github.com/akgarg0472/urlshortener-auth-service/internal/service/notification"
+	tokenService "github.com/akgarg0472/urlshortener-auth-service/internal/service/token"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// an export requested while another one is pending since less than this is rejected
+const pendingDataExportWindow = time.Hour
+
+// ExportData Function to export the data stored about the authenticated user in the requested format. Small exports are
+// returned directly as a file. Large ones are generated in the background and a download link is emailed to the user, in
+// which case only the response is returned
+func ExportData(
+	requestId string,
+	authClaims model.AuthClaims,
+	format string,
+	clientInfo model.ClientInfo,
+) (*model.DataExportFile, *model.DataExportResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Data Export Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+			zap.String("format", format),
+		)
+	}
+
+	exportFormat, err := parseDataExportFormat(format)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := authDao.GetUserEntityById(requestId, authClaims.UserId)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recordsCount, err := countDataExportRecords(requestId, user.Id)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the download link can only be sent by email, so users without one always get the export directly
+	if recordsCount <= int64(utils.GetEnvInt("DATA_EXPORT_SYNC_MAX_RECORDS", 1000)) || user.Email == nil || *user.Email == "" {
+		exportFile, err := generateDataExportFile(requestId, *user, exportFormat)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		auditService.RecordEvent(requestId, constants.AuditEventDataExported, user.Id, clientInfo, map[string]string{
+			"format": string(exportFormat),
+		})
+
+		return exportFile, nil, nil
+	}
+
+	hasPendingExport, err := exportDao.HasPendingDataExport(requestId, user.Id, time.Now().Add(-pendingDataExportWindow).UnixMilli())
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if hasPendingExport {
+		return nil, nil, utils.GetErrorResponse("An export of your data is already being generated. Check your email", 409)
+	}
+
+	validity := getDataExportValidity()
+
+	dataExport := &entity.DataExport{
+		Id:        strings.ReplaceAll(uuid.New().String(), "-", ""),
+		UserId:    user.Id,
+		Format:    exportFormat,
+		Status:    constants.DataExportStatusPending,
+		ExpiresAt: time.Now().Add(validity).UnixMilli(),
+	}
+
+	if err := exportDao.SaveDataExport(requestId, dataExport); err != nil {
+		return nil, nil, err
+	}
+
+	auditService.RecordEvent(requestId, constants.AuditEventDataExportRequested, user.Id, clientInfo, map[string]string{
+		"format":   string(exportFormat),
+		"exportId": dataExport.Id,
+	})
+
+	go generateDataExportInBackground(requestId, *user, *dataExport, validity)
+
+	return nil, &model.DataExportResponse{
+		Success:    true,
+		Message:    "Your data export is being generated. We'll email you a download link once it's ready",
+		ExportId:   dataExport.Id,
+		StatusCode: 202,
+	}, nil
+}
+
+// DownloadDataExport Function to return the export generated in the background using the emailed link. The link can
+// be used until the export expires
+func DownloadDataExport(requestId string, queryParams url.Values, clientInfo model.ClientInfo) (*model.DataExportFile, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Data Export Download Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	downloadToken := queryParams.Get("token")
+
+	if downloadToken == "" {
+		return nil, utils.BadRequestErrorResponse("Token is required")
+	}
+
+	actionToken, err := tokenService.GetInstance().ValidateActionToken(requestId, constants.ActionTokenTypeDataExport, downloadToken)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if actionToken.Payload == nil {
+		return nil, utils.GetErrorResponse("Invalid or expired token", 400)
+	}
+
+	// the export must not be downloadable once the account is deleted
+	if _, err := authDao.GetUserEntityById(requestId, actionToken.UserId); err != nil {
+		if err.ErrorCode == 404 {
+			return nil, utils.GetErrorResponse("Invalid or expired token", 400)
+		}
+		return nil, err
+	}
+
+	dataExport, err := exportDao.GetDataExport(requestId, actionToken.UserId, *actionToken.Payload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if dataExport == nil || dataExport.Status != constants.DataExportStatusReady || dataExport.ExpiresAt < time.Now().UnixMilli() {
+		return nil, utils.GetErrorResponse("Invalid or expired token", 400)
+	}
+
+	auditService.RecordEvent(requestId, constants.AuditEventDataExportDownloaded, dataExport.UserId, clientInfo, map[string]string{
+		"exportId": dataExport.Id,
+	})
+
+	return newDataExportFile(dataExport.Format, dataExport.CreatedAt, dataExport.Data), nil
+}
+
+// InitDataExportPurger periodically removes the expired exports, as they hold a copy of the user data
+func InitDataExportPurger() {
+	go func() {
+		purgeFrequency := utils.GetEnvDurationSeconds("DATA_EXPORT_PURGE_INTERVAL_SECONDS", 1*time.Hour)
+
+		for {
+			time.Sleep(purgeFrequency)
+
+			purged, err := exportDao.DeleteExpiredDataExports("")
+
+			if err == nil && purged > 0 && logger.IsInfoEnabled() {
+				logger.Info("Purged expired data exports",
+					zap.Int64("count", purged),
+				)
+			}
+		}
+	}()
+}
+
+// function to generate the export, store it and email the download link to the user
+func generateDataExportInBackground(requestId string, user entity.User, dataExport entity.DataExport, validity time.Duration) {
+	exportFile, err := generateDataExportFile(requestId, user, dataExport.Format)
+
+	if err != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error generating data export",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.String("exportId", dataExport.Id),
+				zap.Any(constants.ErrorMessageLogKey, err.Message),
+			)
+		}
+		_ = exportDao.CompleteDataExport(requestId, dataExport.Id, nil)
+		return
+	}
+
+	if err := exportDao.CompleteDataExport(requestId, dataExport.Id, exportFile.Data); err != nil {
+		return
+	}
+
+	downloadToken, err := tokenService.GetInstance().IssueActionToken(requestId, user.Id, constants.ActionTokenTypeDataExport, validity, &dataExport.Id)
+
+	if err != nil {
+		return
+	}
+
+	notificationService.SendDataExportReadyEmail(
+		requestId,
+		*user.Email,
+		user.Name,
+		utils.GenerateDataExportDownloadLink(downloadToken),
+		int64(validity.Hours()),
+	)
+}
+
+// function to collect the data of the user and encode it in the format
+func generateDataExportFile(requestId string, user entity.User, format constants.DataExportFormat) (*model.DataExportFile, *model.ErrorResponse) {
+	userDataExport, err := collectUserData(requestId, user)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	var encodeError error
+
+	if format == constants.DataExportFormatZip {
+		data, encodeError = encodeDataExportZip(userDataExport)
+	} else {
+		data, encodeError = json.MarshalIndent(userDataExport, "", "  ")
+	}
+
+	if encodeError != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error("Error encoding data export",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Error(encodeError),
+			)
+		}
+		return nil, utils.InternalServerErrorResponse()
+	}
+
+	return newDataExportFile(format, userDataExport.ExportedAt, data), nil
+}
+
+// function to collect every data of the user. Secrets like password hashes, MFA secrets and tokens are left out
+func collectUserData(requestId string, user entity.User) (*model.UserDataExport, *model.ErrorResponse) {
+	sessions, err := sessionDao.GetSessionsByUserId(requestId, user.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := identityDao.GetUserIdentities(requestId, user.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	userMfa, err := mfaDao.GetUserMfa(requestId, user.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHistory, err := passwordDao.GetPasswordHistory(requestId, user.Id, -1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	auditLogs, err := auditDao.GetAuditLogsByUserId(requestId, user.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	userDataExport := &model.UserDataExport{
+		ExportedAt: time.Now().UnixMilli(),
+		User: model.ExportedUser{
+			UserProfile:           *mapUserToProfile(user),
+			Scopes:                user.Scopes,
+			HasPassword:           user.Password != nil && *user.Password != "",
+			EmailVerifiedAt:       user.EmailVerifiedAt,
+			LastLoginAt:           user.LastLoginAt,
+			LastPasswordChangedAt: user.LastPasswordChangedAt,
+			TokensRevokedAt:       user.TokensRevokedAt,
+		},
+		Sessions:        make([]model.ExportedSession, 0, len(sessions)),
+		Identities:      make([]model.ExportedIdentity, 0, len(identities)),
+		PasswordChanges: make([]int64, 0, len(passwordHistory)),
+		AuditLogs:       make([]model.ExportedAuditLog, 0, len(auditLogs)),
+	}
+
+	for _, session := range sessions {
+		userDataExport.Sessions = append(userDataExport.Sessions, model.ExportedSession{
+			Id:          session.Id,
+			LoginMethod: string(session.LoginMethod),
+			IpAddress:   session.IpAddress,
+			UserAgent:   session.UserAgent,
+			CreatedAt:   session.CreatedAt,
+			LastSeenAt:  session.LastSeenAt,
+			RevokedAt:   session.RevokedAt,
+		})
+	}
+
+	for _, identity := range identities {
+		userDataExport.Identities = append(userDataExport.Identities, model.ExportedIdentity{
+			Provider:       identity.Provider,
+			ProviderUserId: identity.ProviderUserId,
+			Email:          identity.Email,
+			CreatedAt:      identity.CreatedAt,
+			LastLoginAt:    identity.LastLoginAt,
+		})
+	}
+
+	if userMfa != nil {
+		userDataExport.Mfa = &model.ExportedMfa{
+			Enabled:   userMfa.Enabled,
+			EnabledAt: userMfa.EnabledAt,
+			CreatedAt: userMfa.CreatedAt,
+		}
+	}
+
+	for _, passwordChange := range passwordHistory {
+		userDataExport.PasswordChanges = append(userDataExport.PasswordChanges, passwordChange.CreatedAt)
+	}
+
+	for _, auditLog := range auditLogs {
+		exportedAuditLog := model.ExportedAuditLog{
+			Event:     string(auditLog.Event),
+			RequestId: auditLog.RequestId,
+			IpAddress: auditLog.IpAddress,
+			UserAgent: auditLog.UserAgent,
+			CreatedAt: auditLog.CreatedAt,
+		}
+
+		if auditLog.Details != nil && json.Valid([]byte(*auditLog.Details)) {
+			exportedAuditLog.Details = json.RawMessage(*auditLog.Details)
+		}
+
+		userDataExport.AuditLogs = append(userDataExport.AuditLogs, exportedAuditLog)
+	}
+
+	return userDataExport, nil
+}
+
+// function to encode the export as a zip holding one JSON file per kind of data
+func encodeDataExportZip(userDataExport *model.UserDataExport) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	zipWriter := zip.NewWriter(&buffer)
+
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{"user.json", userDataExport.User},
+		{"sessions.json", userDataExport.Sessions},
+		{"identities.json", userDataExport.Identities},
+		{"mfa.json", userDataExport.Mfa},
+		{"password_changes.json", userDataExport.PasswordChanges},
+		{"audit_logs.json", userDataExport.AuditLogs},
+	}
+
+	for _, file := range files {
+		content, err := json.MarshalIndent(file.content, "", "  ")
+
+		if err != nil {
+			return nil, err
+		}
+
+		fileWriter, err := zipWriter.Create(file.name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := fileWriter.Write(content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// function to count the records the export would contain, to decide whether it is generated in the background
+func countDataExportRecords(requestId string, userId string) (int64, *model.ErrorResponse) {
+	sessionsCount, err := sessionDao.CountSessionsByUserId(requestId, userId)
+
+	if err != nil {
+		return 0, err
+	}
+
+	auditLogsCount, err := auditDao.CountAuditLogsByUserId(requestId, userId)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return sessionsCount + auditLogsCount, nil
+}
+
+func parseDataExportFormat(format string) (constants.DataExportFormat, *model.ErrorResponse) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", string(constants.DataExportFormatJson):
+		return constants.DataExportFormatJson, nil
+	case string(constants.DataExportFormatZip):
+		return constants.DataExportFormatZip, nil
+	default:
+		return "", utils.BadRequestErrorResponse("Invalid format, supported formats are json and zip")
+	}
+}
+
+func newDataExportFile(format constants.DataExportFormat, exportedAt int64, data []byte) *model.DataExportFile {
+	fileName := "urlshortener-data-" + time.UnixMilli(exportedAt).UTC().Format("20060102-150405")
+
+	if format == constants.DataExportFormatZip {
+		return &model.DataExportFile{FileName: fileName + ".zip", ContentType: "application/zip", Data: data}
+	}
+
+	return &model.DataExportFile{FileName: fileName + ".json", ContentType: "application/json", Data: data}
+}
+
+func getDataExportValidity() time.Duration {
+	return utils.GetEnvDurationSeconds("DATA_EXPORT_EXPIRY", 48*time.Hour)
+}
@@ -0,0 +1,233 @@
+package user_service
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	authDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/auth"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	auditService "github.com/akgarg0472/urlshortener-auth-service/internal/service/audit"
+	kafkaService "github.com/akgarg0472/urlshortener-auth-service/internal/service/kafka"
+	notificationService "github.com/akgarg0472/urlshortener-auth-service/internal/service/notification"
+	tokenService "github.com/akgarg0472/urlshortener-auth-service/internal/service/token"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+)
+
+// tokens emailed to the previous address which must stop working once the email is changed
+var previousEmailActionTokenTypes = []constants.ActionTokenType{
+	constants.ActionTokenTypeEmailChangeCancel,
+	constants.ActionTokenTypeMagicLink,
+	constants.ActionTokenTypePasswordReset,
+	constants.ActionTokenTypeDataExport,
+}
+
+// RequestEmailChange Function to start the change of the email of the authenticated user after it proved again it owns
+// the account. A confirmation link is sent to the new address and a notice with a cancel link to the current one. The
+// email is only changed once confirmed
+func RequestEmailChange(
+	requestId string,
+	authClaims model.AuthClaims,
+	changeRequest model.ChangeEmailRequest,
+	clientInfo model.ClientInfo,
+) (*model.ChangeEmailResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Change Email Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+			zap.String("newEmail", changeRequest.NewEmail),
+		)
+	}
+
+	newEmail := strings.TrimSpace(changeRequest.NewEmail)
+
+	user, err := authDao.GetUserById(requestId, authClaims.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := reauthenticate(requestId, authClaims, *user, changeRequest.Password, changeRequest.OAuth, clientInfo, "change your email"); err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(newEmail, user.Email) {
+		return nil, utils.BadRequestErrorResponse("New email must be different from the current one")
+	}
+
+	if err := checkEmailAvailable(requestId, newEmail); err != nil {
+		return nil, err
+	}
+
+	validity := utils.GetEnvDurationSeconds("EMAIL_CHANGE_EXPIRY", 24*time.Hour)
+
+	// the new email is kept with the tokens so that confirming applies the address the link was sent to
+	confirmToken, err := tokenService.GetInstance().IssueActionToken(requestId, user.Id, constants.ActionTokenTypeEmailChange, validity, &newEmail)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Email != "" {
+		cancelToken, err := tokenService.GetInstance().IssueActionToken(requestId, user.Id, constants.ActionTokenTypeEmailChangeCancel, validity, &newEmail)
+
+		if err != nil {
+			return nil, err
+		}
+
+		notificationService.SendEmailChangeRequestedEmail(requestId, user.Email, user.Name, newEmail, utils.GenerateEmailChangeCancelLink(cancelToken))
+	}
+
+	notificationService.SendEmailChangeConfirmEmail(requestId, newEmail, user.Name, utils.GenerateEmailChangeConfirmLink(confirmToken), int64(validity.Hours()))
+
+	auditService.RecordEvent(requestId, constants.AuditEventEmailChangeRequested, user.Id, clientInfo, map[string]string{
+		"newEmail": newEmail,
+	})
+
+	return &model.ChangeEmailResponse{
+		Success:    true,
+		Message:    "We have sent a confirmation link to " + newEmail + ". Your email will be changed once you confirm it",
+		StatusCode: 200,
+	}, nil
+}
+
+// ConfirmEmailChange Function to apply the email change using the link sent to the new address and return the frontend
+// URL to redirect to. Every token of the user is revoked as access tokens carry the email, so the user has to log in
+// again using the new address
+func ConfirmEmailChange(requestId string, queryParams url.Values, clientInfo model.ClientInfo) (string, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Confirm Email Change Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	confirmToken := queryParams.Get("token")
+
+	if confirmToken == "" {
+		return "", utils.BadRequestErrorResponse("Token is required")
+	}
+
+	actionToken, err := tokenService.GetInstance().ConsumeActionToken(requestId, constants.ActionTokenTypeEmailChange, confirmToken)
+
+	if err != nil {
+		return "", err
+	}
+
+	if actionToken.Payload == nil {
+		return "", utils.GetErrorResponse("Invalid or expired token", 400)
+	}
+
+	newEmail := *actionToken.Payload
+
+	user, err := authDao.GetUserById(requestId, actionToken.UserId)
+
+	if err != nil {
+		if err.ErrorCode == 404 {
+			return "", utils.GetErrorResponse("Invalid or expired token", 400)
+		}
+		return "", err
+	}
+
+	// the email may have been registered by someone else since the change was requested
+	if err := checkEmailAvailable(requestId, newEmail); err != nil {
+		return "", err
+	}
+
+	if err := authDao.ChangeUserEmail(requestId, user.Id, newEmail); err != nil {
+		return "", err
+	}
+
+	for _, action := range previousEmailActionTokenTypes {
+		_ = tokenService.GetInstance().InvalidateActionTokens(requestId, user.Id, action)
+	}
+
+	if revokeError := tokenService.GetInstance().RevokeAllUserTokens(requestId, user.Id); revokeError != nil {
+		if logger.IsErrorEnabled() {
+			logger.Error(
+				"Error revoking tokens after email change",
+				zap.String(constants.RequestIdLogKey, requestId),
+				zap.Int16(constants.ErrorCodeLogKey, revokeError.ErrorCode),
+				zap.Any(constants.ErrorMessageLogKey, revokeError.Message),
+			)
+		}
+	}
+
+	auditService.RecordEvent(requestId, constants.AuditEventEmailChanged, user.Id, clientInfo, map[string]string{
+		"previousEmail": user.Email,
+		"newEmail":      newEmail,
+	})
+
+	if user.Email != "" {
+		notificationService.SendEmailChangedEmail(requestId, user.Email, user.Name, newEmail)
+	}
+
+	if updatedUser, err := authDao.GetUserEntityById(requestId, user.Id); err == nil {
+		kafkaService.GetInstance().PushUserProfileUpdatedEvent(requestId, model.UserProfileUpdatedEvent{
+			UserId:        updatedUser.Id,
+			UpdatedFields: []string{"email"},
+			Profile:       *mapUserToProfile(*updatedUser),
+			UpdatedAt:     updatedUser.UpdatedAt,
+		})
+	}
+
+	return utils.GenerateEmailChangedRedirectUrl(), nil
+}
+
+// CancelEmailChange Function to cancel the pending email change using the link sent to the current address and return
+// the frontend URL to redirect to
+func CancelEmailChange(requestId string, queryParams url.Values, clientInfo model.ClientInfo) (string, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Cancel Email Change Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+		)
+	}
+
+	cancelToken := queryParams.Get("token")
+
+	if cancelToken == "" {
+		return "", utils.BadRequestErrorResponse("Token is required")
+	}
+
+	actionToken, err := tokenService.GetInstance().ConsumeActionToken(requestId, constants.ActionTokenTypeEmailChangeCancel, cancelToken)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := tokenService.GetInstance().InvalidateActionTokens(requestId, actionToken.UserId, constants.ActionTokenTypeEmailChange); err != nil {
+		return "", err
+	}
+
+	auditService.RecordEvent(requestId, constants.AuditEventEmailChangeCancelled, actionToken.UserId, clientInfo, map[string]string{
+		"newEmail": utils.GetStringOrNil(actionToken.Payload),
+	})
+
+	return utils.GenerateEmailChangeCancelledRedirectUrl(), nil
+}
+
+// function to check that no account, including the deleted ones not purged yet, uses the email
+func checkEmailAvailable(requestId string, email string) *model.ErrorResponse {
+	exists, err := authDao.CheckIfUserExistsByEmail(requestId, email)
+
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		if logger.IsInfoEnabled() {
+			logger.Info(
+				"Email to change to is already registered",
+				zap.String(constants.RequestIdLogKey, requestId),
+			)
+		}
+		return utils.GetErrorResponse("Email already registered", 409)
+	}
+
+	return nil
+}
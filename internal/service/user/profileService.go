@@ -0,0 +1,136 @@
+package user_service
+
+import (
+	"strings"
+
+	"github.com/akgarg0472/urlshortener-auth-service/constants"
+	authDao "github.com/akgarg0472/urlshortener-auth-service/internal/dao/auth"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/entity"
+	"github.com/akgarg0472/urlshortener-auth-service/internal/logger"
+	auditService "github.com/akgarg0472/urlshortener-auth-service/internal/service/audit"
+	kafkaService "github.com/akgarg0472/urlshortener-auth-service/internal/service/kafka"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+	"go.uber.org/zap"
+)
+
+// GetProfile Function to return the profile of the authenticated user
+func GetProfile(requestId string, authClaims model.AuthClaims) (*model.UserProfileResponse, *model.ErrorResponse) {
+	user, err := authDao.GetUserEntityById(requestId, authClaims.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.UserProfileResponse{
+		Success:    true,
+		Profile:    mapUserToProfile(*user),
+		StatusCode: 200,
+	}, nil
+}
+
+// UpdateProfile Function to update the fields of the profile present in the request. The update is rejected with 409
+// if the profile was updated since the client read it, so that concurrent edits don't silently overwrite each other
+func UpdateProfile(
+	requestId string,
+	authClaims model.AuthClaims,
+	updateRequest model.UpdateProfileRequest,
+	clientInfo model.ClientInfo,
+) (*model.UserProfileResponse, *model.ErrorResponse) {
+	if logger.IsInfoEnabled() {
+		logger.Info(
+			"Processing Update Profile Request",
+			zap.String(constants.RequestIdLogKey, requestId),
+			zap.String("userId", authClaims.UserId),
+			zap.String("request", updateRequest.String()),
+		)
+	}
+
+	updatedFields := updateRequest.UpdatedFields()
+
+	if len(updatedFields) == 0 {
+		return nil, utils.BadRequestErrorResponse("No profile field to update")
+	}
+
+	if updateRequest.Name != nil && strings.TrimSpace(*updateRequest.Name) == "" {
+		return nil, &model.ErrorResponse{
+			Message:   "Request validation failed",
+			Errors:    map[string]string{"Name": "Name can't be empty"},
+			ErrorCode: 400,
+		}
+	}
+
+	columns := make(map[string]interface{})
+
+	setProfileColumn(columns, "Name", updateRequest.Name)
+	setProfileColumn(columns, "Bio", updateRequest.Bio)
+	setProfileColumn(columns, "ProfilePictureURL", updateRequest.ProfilePictureURL)
+	setProfileColumn(columns, "Phone", updateRequest.Phone)
+	setProfileColumn(columns, "City", updateRequest.City)
+	setProfileColumn(columns, "State", updateRequest.State)
+	setProfileColumn(columns, "Country", updateRequest.Country)
+	setProfileColumn(columns, "Zipcode", updateRequest.Zipcode)
+	setProfileColumn(columns, "BusinessDetails", updateRequest.BusinessDetails)
+
+	user, err := authDao.UpdateUserProfile(requestId, authClaims.UserId, updateRequest.UpdatedAt, columns)
+
+	if err != nil {
+		return nil, err
+	}
+
+	profile := mapUserToProfile(*user)
+
+	auditService.RecordEvent(requestId, constants.AuditEventProfileUpdated, user.Id, clientInfo, map[string]string{
+		"fields": strings.Join(updatedFields, ","),
+	})
+
+	kafkaService.GetInstance().PushUserProfileUpdatedEvent(requestId, model.UserProfileUpdatedEvent{
+		UserId:        user.Id,
+		UpdatedFields: updatedFields,
+		Profile:       *profile,
+		UpdatedAt:     user.UpdatedAt,
+	})
+
+	return &model.UserProfileResponse{
+		Success:    true,
+		Profile:    profile,
+		StatusCode: 200,
+	}, nil
+}
+
+// function to add the column to update if the request has its value. Name is required, the other empty values clear the
+// column
+func setProfileColumn(columns map[string]interface{}, column string, value *string) {
+	if value == nil {
+		return
+	}
+
+	trimmedValue := strings.TrimSpace(*value)
+
+	if trimmedValue == "" && column != "Name" {
+		columns[column] = nil
+		return
+	}
+
+	columns[column] = trimmedValue
+}
+
+func mapUserToProfile(user entity.User) *model.UserProfile {
+	return &model.UserProfile{
+		Id:                user.Id,
+		Name:              user.Name,
+		Email:             user.Email,
+		EmailVerified:     user.EmailVerified,
+		LoginType:         string(user.UserLoginType),
+		Bio:               user.Bio,
+		ProfilePictureURL: user.ProfilePictureURL,
+		Phone:             user.Phone,
+		City:              user.City,
+		State:             user.State,
+		Country:           user.Country,
+		Zipcode:           user.Zipcode,
+		BusinessDetails:   user.BusinessDetails,
+		CreatedAt:         user.CreatedAt,
+		UpdatedAt:         user.UpdatedAt,
+	}
+}
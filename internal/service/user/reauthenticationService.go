@@ -0,0 +1,44 @@
+package user_service
+
+import (
+	oauthService "github.com/akgarg0472/urlshortener-auth-service/internal/service/auth/oauth"
+	throttleService "github.com/akgarg0472/urlshortener-auth-service/internal/service/throttle"
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+	"github.com/akgarg0472/urlshortener-auth-service/utils"
+)
+
+// function to check the proof of ownership required before a sensitive operation, described by operation in the error
+// messages. Users having a password must provide it, the others must complete an authorization request with one of
+// their linked providers
+func reauthenticate(
+	requestId string,
+	authClaims model.AuthClaims,
+	user model.User,
+	password string,
+	oAuthRequest *model.OAuthCallbackRequest,
+	clientInfo model.ClientInfo,
+	operation string,
+) *model.ErrorResponse {
+	if user.Password == "" {
+		if oAuthRequest == nil {
+			return utils.BadRequestErrorResponse("Log in again with one of your linked accounts to " + operation)
+		}
+		return oauthService.VerifyReauthentication(requestId, authClaims, *oAuthRequest)
+	}
+
+	if password == "" {
+		return utils.BadRequestErrorResponse("Password is required to " + operation)
+	}
+
+	passwordValid, throttleError := throttleService.VerifyPasswordWithThrottle(requestId, user, password, clientInfo)
+
+	if throttleError != nil {
+		return throttleError
+	}
+
+	if !passwordValid {
+		return utils.GetErrorResponse("Password is incorrect", 401)
+	}
+
+	return nil
+}
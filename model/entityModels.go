@@ -5,20 +5,44 @@ import (
 )
 
 type User struct {
-	Id                  string
-	Name                string
-	Email               string
-	Password            string
-	Scopes              string
-	ForgotPasswordToken string
-	OAuthId             string
-	OAuthProvider       string
-	LastLoginAt         int64
-	PasswordChangedAt   int64
-	IsDeleted           bool
-	LoginType           constants.UserEntityLoginType
+	Id                string
+	Name              string
+	Email             string
+	Password          string
+	Scopes            string
+	LastLoginAt       int64
+	PasswordChangedAt int64
+	TokensRevokedAt   int64
+	IsDeleted         bool
+	EmailVerified     bool
+	LoginType         constants.UserEntityLoginType
 }
 
 func (u User) String() string {
-	return "{id=" + u.Id + ", name=" + u.Name + ", email=" + u.Email + ", oAuthId=" + u.OAuthId + ", OAuthProvider=" + u.OAuthProvider + ", scopes=" + u.Scopes + "}"
+	return "{id=" + u.Id + ", name=" + u.Name + ", email=" + u.Email + ", scopes=" + u.Scopes + "}"
+}
+
+type AuthClaims struct {
+	UserId    string
+	Email     string
+	Scopes    string
+	TokenId   string
+	SessionId string
+	IssuedAt  int64
+	ExpiresAt int64
+}
+
+func (c AuthClaims) String() string {
+	return "{userId=" + c.UserId + ", email=" + c.Email + ", scopes=" + c.Scopes + ", tokenId=" + c.TokenId + ", sessionId=" + c.SessionId + "}"
+}
+
+type ClientInfo struct {
+	IpAddress string
+	UserAgent string
+}
+
+type SessionTokens struct {
+	SessionId    string
+	AccessToken  string
+	RefreshToken string
 }
@@ -22,3 +22,19 @@ func (event *NotificationEvent) String() string {
 type UserRegisteredEvent struct {
 	UserId string `json:"user_id"`
 }
+
+// UserProfileUpdatedEvent is pushed after the profile of a user is updated, with the profile as stored after the update
+type UserProfileUpdatedEvent struct {
+	UserId        string      `json:"user_id"`
+	UpdatedFields []string    `json:"updated_fields"`
+	Profile       UserProfile `json:"profile"`
+	UpdatedAt     int64       `json:"updated_at"`
+}
+
+// UserDeletedEvent is pushed once the grace period of a deleted user is over and its data is purged, so that the other
+// services can delete the data of the user as well
+type UserDeletedEvent struct {
+	UserId    string `json:"user_id"`
+	DeletedAt int64  `json:"deleted_at"`
+	PurgedAt  int64  `json:"purged_at"`
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/akgarg0472/urlshortener-auth-service/constants"
@@ -18,6 +19,14 @@ func (r LoginRequest) String() string {
 	return fmt.Sprintf("{Email: %s, Password: %s}", r.Email, maskedPassword)
 }
 
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+func (r RefreshTokenRequest) String() string {
+	return fmt.Sprintf("{RefreshToken: %s}", maskString(r.RefreshToken, false))
+}
+
 type SignupRequest struct {
 	Name            string `json:"name" validate:"required"`
 	Email           string `json:"email" validate:"required"`
@@ -30,11 +39,14 @@ func (r SignupRequest) String() string {
 }
 
 type LogoutRequest struct {
-	UserId string `json:"user_id" validate:"required"`
+	UserId       string `json:"user_id" validate:"required"`
+	AuthToken    string `json:"auth_token"`
+	RefreshToken string `json:"refresh_token"`
+	AllDevices   bool   `json:"all_devices"`
 }
 
 func (r LogoutRequest) String() string {
-	return fmt.Sprintf("{UserId: %s}", r.UserId)
+	return fmt.Sprintf("{UserId: %s, AllDevices: %t}", r.UserId, r.AllDevices)
 }
 
 type ValidateTokenRequest struct {
@@ -65,8 +77,17 @@ func (r ResetPasswordRequest) String() string {
 	return fmt.Sprintf("{ResetPasswordToken: %s, Email: %s, Password: %s, ConfirmPassword: %s}", maskString(r.ResetPasswordToken, false), r.Email, maskString(r.Password, true), maskString(r.ConfirmPassword, true))
 }
 
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+func (r ChangePasswordRequest) String() string {
+	return fmt.Sprintf("{CurrentPassword: %s, NewPassword: %s}", maskString(r.CurrentPassword, true), maskString(r.NewPassword, true))
+}
+
 type OAuthCallbackRequest struct {
-	State    string                  `json:"state"`
+	State    string                  `json:"state" validate:"required"`
 	Code     string                  `json:"auth_code"`
 	Scope    string                  `json:"scope"`
 	Provider constants.OAuthProvider `json:"provider"`
@@ -76,6 +97,15 @@ func (r OAuthCallbackRequest) String() string {
 	return fmt.Sprintf("{State: %s, Code: %s, Scope: %s, Provider: %s}", maskString(r.State, false), maskString(r.Code, true), r.Scope, r.Provider)
 }
 
+type LinkOAuthIdentityRequest struct {
+	Password string                `json:"password"`
+	OAuth    *OAuthCallbackRequest `json:"oauth"`
+}
+
+func (r LinkOAuthIdentityRequest) String() string {
+	return fmt.Sprintf("{Password: %s, OAuth: %v}", maskString(r.Password, true), r.OAuth)
+}
+
 type VerifyAdminRequest struct {
 	UserId string `json:"user_id"`
 }
@@ -84,6 +114,24 @@ func (r VerifyAdminRequest) String() string {
 	return fmt.Sprintf("{UserId: %s}", r.UserId)
 }
 
+type AddSigningKeyRequest struct {
+	Kid            string `json:"kid" validate:"required,max=64"`
+	Algorithm      string `json:"algorithm" validate:"required,oneof=RS256 ES256"`
+	PrivateKeyPath string `json:"private_key_path" validate:"required"`
+}
+
+func (r AddSigningKeyRequest) String() string {
+	return fmt.Sprintf("{Kid: %s, Algorithm: %s, PrivateKeyPath: %s}", r.Kid, r.Algorithm, r.PrivateKeyPath)
+}
+
+type RetireSigningKeyRequest struct {
+	RetireAt int64 `json:"retire_at"`
+}
+
+func (r RetireSigningKeyRequest) String() string {
+	return fmt.Sprintf("{RetireAt: %d}", r.RetireAt)
+}
+
 func maskString(input string, isPassword bool) string {
 	if len(input) == 0 {
 		return input
@@ -115,3 +163,96 @@ func maskString(input string, isPassword bool) string {
 
 	return string(maskedArray)
 }
+
+type TotpCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type MfaLoginRequest struct {
+	MfaToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type LoginOtpRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type LoginOtpVerifyRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Code  string `json:"code" validate:"required,numeric"`
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResendVerificationEmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// UpdateProfileRequest holds the profile fields to update, fields left out are unchanged and optional fields set to an
+// empty string are cleared. UpdatedAt must be the updated_at of the profile the changes were made on
+type UpdateProfileRequest struct {
+	Name              *string `json:"name" validate:"omitempty,max=255"`
+	Bio               *string `json:"bio" validate:"omitempty,max=1000"`
+	ProfilePictureURL *string `json:"profile_picture_url" validate:"omitempty,max=2048,http_url|len=0"`
+	Phone             *string `json:"phone" validate:"omitempty,e164|len=0"`
+	City              *string `json:"city" validate:"omitempty,max=50"`
+	State             *string `json:"state" validate:"omitempty,max=50"`
+	Country           *string `json:"country" validate:"omitempty,iso3166_1_alpha2|len=0"`
+	Zipcode           *string `json:"zipcode" validate:"omitempty,max=16,zipcode|len=0"`
+	BusinessDetails   *string `json:"business_details" validate:"omitempty,max=2000"`
+	UpdatedAt         int64   `json:"updated_at" validate:"required"`
+}
+
+func (r UpdateProfileRequest) String() string {
+	return fmt.Sprintf("{UpdatedFields: %v, UpdatedAt: %d}", r.UpdatedFields(), r.UpdatedAt)
+}
+
+// UpdatedFields returns the JSON names of the fields present in the request
+func (r UpdateProfileRequest) UpdatedFields() []string {
+	fields := make([]string, 0)
+
+	for name, value := range map[string]*string{
+		"name":                r.Name,
+		"bio":                 r.Bio,
+		"profile_picture_url": r.ProfilePictureURL,
+		"phone":               r.Phone,
+		"city":                r.City,
+		"state":               r.State,
+		"country":             r.Country,
+		"zipcode":             r.Zipcode,
+		"business_details":    r.BusinessDetails,
+	} {
+		if value != nil {
+			fields = append(fields, name)
+		}
+	}
+
+	sort.Strings(fields)
+
+	return fields
+}
+
+// DeleteAccountRequest holds the proof of ownership required to delete the account, the password for users having one
+// or else the result of an authorization request started using /users/me/reauthenticate/{provider}
+type DeleteAccountRequest struct {
+	Password string                `json:"password"`
+	OAuth    *OAuthCallbackRequest `json:"oauth"`
+}
+
+func (r DeleteAccountRequest) String() string {
+	return fmt.Sprintf("{Password: %s, OAuth: %v}", maskString(r.Password, true), r.OAuth)
+}
+
+// ChangeEmailRequest holds the address the user wants to change its email to, and the proof the user owns the account.
+// The change is applied once the link sent to it is confirmed
+type ChangeEmailRequest struct {
+	NewEmail string                `json:"new_email" validate:"required,email,max=255"`
+	Password string                `json:"password"`
+	OAuth    *OAuthCallbackRequest `json:"oauth"`
+}
+
+func (r ChangeEmailRequest) String() string {
+	return fmt.Sprintf("{NewEmail: %s, Password: %s, OAuth: %v}", r.NewEmail, maskString(r.Password, true), r.OAuth)
+}
@@ -1,13 +1,142 @@
 package model
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 type LoginResponse struct {
-	AccessToken string `json:"auth_token"`
-	UserId      string `json:"user_id"`
-	Name        string `json:"name"`
-	Email       string `json:"email"`
-	LoginType   string `json:"login_type"`
+	AccessToken   string `json:"auth_token,omitempty"`
+	RefreshToken  string `json:"refresh_token,omitempty"`
+	UserId        string `json:"user_id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	LoginType     string `json:"login_type"`
+	MfaRequired   bool   `json:"mfa_required"`
+	MfaToken      string `json:"mfa_token,omitempty"`
+}
+
+type RefreshTokenResponse struct {
+	AccessToken  string `json:"auth_token"`
+	RefreshToken string `json:"refresh_token"`
+	UserId       string `json:"user_id"`
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKSResponse struct {
+	Keys []JWK `json:"keys"`
+}
+
+type SigningKeyInfo struct {
+	Kid       string `json:"kid"`
+	Algorithm string `json:"algorithm"`
+	IsCurrent bool   `json:"is_current"`
+	Status    string `json:"status"`
+	RetireAt  *int64 `json:"retire_at,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type SigningKeysResponse struct {
+	Keys       []SigningKeyInfo `json:"keys"`
+	Success    bool             `json:"success"`
+	StatusCode int              `json:"status_code"`
+}
+
+type SigningKeyResponse struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code"`
+}
+
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Sub       string `json:"sub,omitempty"`
+	UserId    string `json:"uid,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Iss       string `json:"iss,omitempty"`
+	ClientId  string `json:"client_id,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+}
+
+type SessionInfo struct {
+	Id          string `json:"id"`
+	UserAgent   string `json:"user_agent"`
+	IpAddress   string `json:"ip_address"`
+	LoginMethod string `json:"login_method"`
+	CreatedAt   int64  `json:"created_at"`
+	LastSeenAt  int64  `json:"last_seen_at"`
+	Current     bool   `json:"current"`
+}
+
+type SessionsResponse struct {
+	Sessions   []SessionInfo `json:"sessions"`
+	Success    bool          `json:"success"`
+	StatusCode int           `json:"status_code"`
+}
+
+type SessionResponse struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code"`
+}
+
+type UnlockAccountResponse struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code"`
+}
+
+type TotpEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthUri string `json:"otpauth_uri"`
+	QrPayload  string `json:"qr_payload"`
+	Success    bool   `json:"success"`
+	StatusCode int    `json:"status_code"`
+}
+
+type TotpConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+	Success       bool     `json:"success"`
+	Message       string   `json:"message"`
+	StatusCode    int      `json:"status_code"`
+}
+
+type MfaResponse struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code"`
+}
+
+type LoginOtpResponse struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code"`
+}
+
+type MagicLinkResponse struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code"`
+}
+
+type ResendVerificationEmailResponse struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code"`
 }
 
 type SignupResponse struct {
@@ -16,9 +145,15 @@ type SignupResponse struct {
 }
 
 type ErrorResponse struct {
-	Message   interface{} `json:"message"`
-	ErrorCode int16       `json:"error_code"`
-	Errors    interface{} `json:"errors"`
+	Message    interface{} `json:"message"`
+	ErrorCode  int16       `json:"error_code"`
+	Errors     interface{} `json:"errors"`
+	RetryAfter int64       `json:"-"`
+}
+
+type PasswordPolicyViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
 }
 
 type LogoutResponse struct {
@@ -44,6 +179,18 @@ type ResetPasswordResponse struct {
 	StatusCode int    `json:"status_code"`
 }
 
+type PasswordSetupResponse struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code"`
+}
+
+type ChangePasswordResponse struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code"`
+}
+
 type VerifyAdminResponse struct {
 	Success    bool   `json:"success"`
 	Message    string `json:"message"`
@@ -52,6 +199,7 @@ type VerifyAdminResponse struct {
 
 type OAuthProvider struct {
 	Provider    string `json:"provider"`
+	Type        string `json:"type"`
 	ClientId    string `json:"client_id"`
 	BaseUrl     string `json:"base_url"`
 	RedirectURI string `json:"redirect_uri"`
@@ -65,17 +213,162 @@ type OAuthProviderResponse struct {
 	StatusCode int             `json:"status_code"`
 }
 
+type OAuthAuthorizeResponse struct {
+	Success          bool   `json:"success"`
+	AuthorizationUrl string `json:"authorization_url"`
+	State            string `json:"state"`
+	ExpiresAt        int64  `json:"expires_at"`
+	StatusCode       int    `json:"status_code"`
+	// BrowserBinding is the secret set in a cookie of the browser starting a login, it is never part of the body
+	BrowserBinding string `json:"-"`
+}
+
+type OAuthIdentity struct {
+	Id          string  `json:"id"`
+	Provider    string  `json:"provider"`
+	Email       *string `json:"email,omitempty"`
+	CreatedAt   int64   `json:"created_at"`
+	LastLoginAt *int64  `json:"last_login_at,omitempty"`
+}
+
+type OAuthIdentitiesResponse struct {
+	Success    bool            `json:"success"`
+	Identities []OAuthIdentity `json:"identities"`
+	StatusCode int             `json:"status_code"`
+}
+
+type LinkOAuthIdentityResponse struct {
+	Success    bool           `json:"success"`
+	Message    string         `json:"message"`
+	Identity   *OAuthIdentity `json:"identity"`
+	StatusCode int            `json:"status_code"`
+}
+
+type UnlinkOAuthIdentityResponse struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code"`
+}
+
 type OAuthCallbackResponse struct {
-	Success   bool   `json:"success"`
-	UserId    string `json:"user_id"`
-	AuthToken string `json:"auth_token"`
-	Email     string `json:"email"`
-	Name      string `json:"name"`
-	IsNewUser bool   `json:"is_new_user"`
-	Message   string `json:"message"`
-	LoginType string `json:"login_type"`
+	Success      bool   `json:"success"`
+	UserId       string `json:"user_id"`
+	AuthToken    string `json:"auth_token"`
+	RefreshToken string `json:"refresh_token"`
+	Email        string `json:"email"`
+	Name         string `json:"name"`
+	IsNewUser    bool   `json:"is_new_user"`
+	Message      string `json:"message"`
+	LoginType    string `json:"login_type"`
+	MfaRequired  bool   `json:"mfa_required"`
+	MfaToken     string `json:"mfa_token,omitempty"`
 }
 
 func (c OAuthProvider) String() string {
-	return fmt.Sprintf("OAuthProvider{Provider: %s, ClientId: %s, RedirectURI: %s, AccessType: %s, Scope: %s}", c.Provider, c.ClientId, c.RedirectURI, c.AccessType, c.Scope)
+	return fmt.Sprintf("OAuthProvider{Provider: %s, Type: %s, ClientId: %s, RedirectURI: %s, AccessType: %s, Scope: %s}", c.Provider, c.Type, c.ClientId, c.RedirectURI, c.AccessType, c.Scope)
+}
+
+type UserProfile struct {
+	Id                string  `json:"id"`
+	Name              string  `json:"name"`
+	Email             *string `json:"email"`
+	EmailVerified     bool    `json:"email_verified"`
+	LoginType         string  `json:"login_type"`
+	Bio               *string `json:"bio"`
+	ProfilePictureURL *string `json:"profile_picture_url"`
+	Phone             *string `json:"phone"`
+	City              *string `json:"city"`
+	State             *string `json:"state"`
+	Country           *string `json:"country"`
+	Zipcode           *string `json:"zipcode"`
+	BusinessDetails   *string `json:"business_details"`
+	CreatedAt         int64   `json:"created_at"`
+	UpdatedAt         int64   `json:"updated_at"`
+}
+
+type UserProfileResponse struct {
+	Success    bool         `json:"success"`
+	Profile    *UserProfile `json:"profile"`
+	StatusCode int          `json:"status_code"`
+}
+
+type DeleteAccountResponse struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	PurgeAt    int64  `json:"purge_at"`
+	StatusCode int    `json:"status_code"`
+}
+
+type DataExportResponse struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	ExportId   string `json:"export_id"`
+	StatusCode int    `json:"status_code"`
+}
+
+// DataExportFile is the encoded export sent to the user as an attachment
+type DataExportFile struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// UserDataExport holds everything the service stores about the user, except secrets like password hashes and tokens
+type UserDataExport struct {
+	ExportedAt      int64              `json:"exported_at"`
+	User            ExportedUser       `json:"user"`
+	Sessions        []ExportedSession  `json:"sessions"`
+	Identities      []ExportedIdentity `json:"identities"`
+	Mfa             *ExportedMfa       `json:"mfa"`
+	PasswordChanges []int64            `json:"password_changes"`
+	AuditLogs       []ExportedAuditLog `json:"audit_logs"`
+}
+
+type ExportedUser struct {
+	UserProfile
+	Scopes                string `json:"scopes"`
+	HasPassword           bool   `json:"has_password"`
+	EmailVerifiedAt       *int64 `json:"email_verified_at"`
+	LastLoginAt           *int64 `json:"last_login_at"`
+	LastPasswordChangedAt *int64 `json:"last_password_changed_at"`
+	TokensRevokedAt       *int64 `json:"tokens_revoked_at"`
+}
+
+type ExportedSession struct {
+	Id          string `json:"id"`
+	LoginMethod string `json:"login_method"`
+	IpAddress   string `json:"ip_address"`
+	UserAgent   string `json:"user_agent"`
+	CreatedAt   int64  `json:"created_at"`
+	LastSeenAt  int64  `json:"last_seen_at"`
+	RevokedAt   *int64 `json:"revoked_at"`
+}
+
+type ExportedIdentity struct {
+	Provider       string  `json:"provider"`
+	ProviderUserId string  `json:"provider_user_id"`
+	Email          *string `json:"email"`
+	CreatedAt      int64   `json:"created_at"`
+	LastLoginAt    *int64  `json:"last_login_at"`
+}
+
+type ExportedMfa struct {
+	Enabled   bool   `json:"enabled"`
+	EnabledAt *int64 `json:"enabled_at"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type ExportedAuditLog struct {
+	Event     string          `json:"event"`
+	RequestId string          `json:"request_id"`
+	IpAddress string          `json:"ip_address"`
+	UserAgent string          `json:"user_agent"`
+	Details   json.RawMessage `json:"details,omitempty"`
+	CreatedAt int64           `json:"created_at"`
+}
+
+type ChangeEmailResponse struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code"`
 }
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// GenerateSecureRandomToken returns a URL safe random token built from the given number of random bytes
+func GenerateSecureRandomToken(size int) (string, error) {
+	randomBytes := make([]byte, size)
+
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+// GenerateNumericCode returns a uniformly random code made of the given number of digits
+func GenerateNumericCode(digits int) (string, error) {
+	upperBound := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+
+	value, err := rand.Int(rand.Reader, upperBound)
+
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*s", digits, value.String()), nil
+}
+
+// HashToken returns the hex encoded SHA-256 digest of the token
+func HashToken(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(digest[:])
+}
+
+// EncryptAESGCM encrypts the plaintext with AES-GCM and returns the base64 encoded nonce followed by the ciphertext
+func EncryptAESGCM(key []byte, plaintext string) (string, error) {
+	gcm, err := newGCM(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptAESGCM decrypts the value produced by EncryptAESGCM
+func DecryptAESGCM(key []byte, encrypted string) (string, error) {
+	gcm, err := newGCM(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("encrypted value is too short")
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -31,3 +31,23 @@ func GetEnvDurationSeconds(envVar string, defaultValue time.Duration) time.Durat
 
 	return time.Duration(parsedValue) * time.Second
 }
+
+func GetEnvInt(envVar string, defaultValue int) int {
+	parsedValue, err := strconv.Atoi(os.Getenv(envVar))
+
+	if err != nil {
+		return defaultValue
+	}
+
+	return parsedValue
+}
+
+func GetEnvBool(envVar string, defaultValue bool) bool {
+	parsedValue, err := strconv.ParseBool(os.Getenv(envVar))
+
+	if err != nil {
+		return defaultValue
+	}
+
+	return parsedValue
+}
@@ -1,5 +1,7 @@
 package utils
 
+import "strconv"
+
 const defaultLogoLink = "https://res.cloudinary.com/dmdbqq7fp/bysb90sd8dsjst6ieeno.png"
 
 func GetSignupSuccessEmailBody(name string) string {
@@ -12,6 +14,46 @@ func GenerateForgotPasswordEmailBody(email string, name string, forgotPasswordUr
 	return "<div style='font-family:Arial,sans-serif;margin:0;padding:0;background: linear-gradient(to bottom, #f7f8f9, #ffffff)!important;max-width:600px;margin:20px auto;padding:20px;background-color:#fff;border-radius:5px;box-shadow:0 0 10px rgba(0,0,0,0.1);color:#333;text-align:center;'><p style='font-size:16px;text-align:left'>Hello " + name + ",</p><p style='text-align:left;line-height:24px;font-size:16px;'>We received a request to reset the password for your URLShortener account associated with <span style='color:#15c'>" + email + "</span>.</p><p style='font-size:16px;text-align:left;margin-top:24px;'>To reset your password, click the button below:</p><a href='" + forgotPasswordUrl + "' style='display:inline-block;padding:10px 20px;text-decoration:none;background-color:#5063f0;color:#fff;border-radius:6px;'>Reset Password</a><p style='text-align:left;margin-top:24px;line-height:24px;font-size:16px;'>If you didn't make this request, or if you're having trouble signing in, contact us via our support site. No changes have been made to your account.</p><div style='text-align:left;font-size:16px;margin-top:24px;'>- URLShortener Team</div><div style='padding:10px;border-radius:0 0 5px 5px;margin-top:20px;font-size:12px;line-height:18px;'>UrlShortener is a hobby project by Akhilesh Garg</div>"
 }
 
+func GenerateSetPasswordEmailBody(email string, name string, setPasswordUrl string) string {
+	return "<div style='font-family:Arial,sans-serif;margin:0;padding:0;background: linear-gradient(to bottom, #f7f8f9, #ffffff)!important;max-width:600px;margin:20px auto;padding:20px;background-color:#fff;border-radius:5px;box-shadow:0 0 10px rgba(0,0,0,0.1);color:#333;text-align:center;'><p style='font-size:16px;text-align:left'>Hello " + name + ",</p><p style='text-align:left;line-height:24px;font-size:16px;'>We received a request to add a password to your URLShortener account associated with <span style='color:#15c'>" + email + "</span>. Once it is set, you can log in using your email and password as well as your linked accounts.</p><p style='font-size:16px;text-align:left;margin-top:24px;'>To set your password, click the button below:</p><a href='" + setPasswordUrl + "' style='display:inline-block;padding:10px 20px;text-decoration:none;background-color:#5063f0;color:#fff;border-radius:6px;'>Set Password</a><p style='text-align:left;margin-top:24px;line-height:24px;font-size:16px;'>If you didn't make this request, you can safely ignore this email. No changes have been made to your account.</p><div style='text-align:left;font-size:16px;margin-top:24px;'>- URLShortener Team</div><div style='padding:10px;border-radius:0 0 5px 5px;margin-top:20px;font-size:12px;line-height:18px;'>UrlShortener is a hobby project by Akhilesh Garg</div>"
+}
+
 func GeneratePasswordChangeSuccessEmailBody(email string) string {
 	return "<div style='font-family:Arial,sans-serif;margin:0;padding:0;background: linear-gradient(to bottom, #f7f8f9, #ffffff)!important;max-width:600px;margin:20px auto;padding:20px;background-color:#fff;border-radius:5px;box-shadow:0 0 10px rgba(0,0,0,0.1);color:#333;text-align:center;'><p style='font-size:16px;text-align:left'>Dear User,</p><p style='text-align:left;line-height:24px;font-size:16px;'>The password of your account associated with <span style='color:#15c'>" + email + "</span> has been successfully changed. If you made this change, no further action is needed.</p><p style='text-align:left;margin-top:24px;line-height:24px;font-size:16px;'>If you didn't request this, please change your password immediately & contact us via our support site. No changes have been made to your account.</p><div style='text-align:left;font-size:16px;margin-top:24px;'>- URLShortener Team</div><div style='padding:10px;border-radius:0 0 5px 5px;margin-top:20px;font-size:12px;line-height:18px;'>UrlShortener is a hobby project by Akhilesh Garg</div>"
 }
+
+func GenerateAccountLockedEmailBody(email string, lockedUntil string) string {
+	return "<div style='font-family:Arial,sans-serif;margin:0;padding:0;background: linear-gradient(to bottom, #f7f8f9, #ffffff)!important;max-width:600px;margin:20px auto;padding:20px;background-color:#fff;border-radius:5px;box-shadow:0 0 10px rgba(0,0,0,0.1);color:#333;text-align:center;'><p style='font-size:16px;text-align:left'>Dear User,</p><p style='text-align:left;line-height:24px;font-size:16px;'>Your account associated with <span style='color:#15c'>" + email + "</span> has been temporarily locked after too many failed login attempts. You can try logging in again after " + lockedUntil + ".</p><p style='text-align:left;margin-top:24px;line-height:24px;font-size:16px;'>If these attempts were not made by you, we recommend changing your password once the lock expires & contacting us via our support site.</p><div style='text-align:left;font-size:16px;margin-top:24px;'>- URLShortener Team</div><div style='padding:10px;border-radius:0 0 5px 5px;margin-top:20px;font-size:12px;line-height:18px;'>UrlShortener is a hobby project by Akhilesh Garg</div>"
+}
+
+func GenerateLoginOtpEmailBody(name string, code string, validityMinutes int64) string {
+	return "<div style='font-family:Arial,sans-serif;margin:0;padding:0;background: linear-gradient(to bottom, #f7f8f9, #ffffff)!important;max-width:600px;margin:20px auto;padding:20px;background-color:#fff;border-radius:5px;box-shadow:0 0 10px rgba(0,0,0,0.1);color:#333;text-align:center;'><p style='font-size:16px;text-align:left'>Dear " + name + ",</p><p style='text-align:left;line-height:24px;font-size:16px;'>Use the following one-time code to log in to your UrlShortener account. The code is valid for " + strconv.FormatInt(validityMinutes, 10) + " minutes.</p><p style='font-size:28px;letter-spacing:6px;font-weight:bold;margin:24px 0;'>" + code + "</p><p style='text-align:left;margin-top:24px;line-height:24px;font-size:16px;'>If you didn't request this code, you can safely ignore this email. Never share this code with anyone.</p><div style='text-align:left;font-size:16px;margin-top:24px;'>- URLShortener Team</div><div style='padding:10px;border-radius:0 0 5px 5px;margin-top:20px;font-size:12px;line-height:18px;'>UrlShortener is a hobby project by Akhilesh Garg</div>"
+}
+
+func GenerateMagicLinkEmailBody(name string, magicLink string, validityMinutes int64) string {
+	return "<div style='font-family:Arial,sans-serif;margin:0;padding:0;background: linear-gradient(to bottom, #f7f8f9, #ffffff)!important;max-width:600px;margin:20px auto;padding:20px;background-color:#fff;border-radius:5px;box-shadow:0 0 10px rgba(0,0,0,0.1);color:#333;text-align:center;'><p style='font-size:16px;text-align:left'>Dear " + name + ",</p><p style='text-align:left;line-height:24px;font-size:16px;'>Click the button below to log in to your UrlShortener account. The link is valid for " + strconv.FormatInt(validityMinutes, 10) + " minutes and can be used only once.</p><a href='" + magicLink + "' style='display:inline-block;margin:24px 0;padding:12px 24px;background-color:#15c;color:#fff;text-decoration:none;border-radius:5px;font-size:16px;'>Log in to UrlShortener</a><p style='text-align:left;margin-top:24px;line-height:24px;font-size:16px;'>If you didn't request this link, you can safely ignore this email.</p><div style='text-align:left;font-size:16px;margin-top:24px;'>- URLShortener Team</div><div style='padding:10px;border-radius:0 0 5px 5px;margin-top:20px;font-size:12px;line-height:18px;'>UrlShortener is a hobby project by Akhilesh Garg</div>"
+}
+
+func GenerateEmailVerificationEmailBody(name string, verificationLink string, validityHours int64) string {
+	return "<div style='font-family:Arial,sans-serif;margin:0;padding:0;background: linear-gradient(to bottom, #f7f8f9, #ffffff)!important;max-width:600px;margin:20px auto;padding:20px;background-color:#fff;border-radius:5px;box-shadow:0 0 10px rgba(0,0,0,0.1);color:#333;text-align:center;'><p style='font-size:16px;text-align:left'>Dear " + name + ",</p><p style='text-align:left;line-height:24px;font-size:16px;'>Thanks for signing up for UrlShortener! Please confirm your email address by clicking the button below. The link is valid for " + strconv.FormatInt(validityHours, 10) + " hours.</p><a href='" + verificationLink + "' style='display:inline-block;margin:24px 0;padding:12px 24px;background-color:#15c;color:#fff;text-decoration:none;border-radius:5px;font-size:16px;'>Verify Email</a><p style='text-align:left;margin-top:24px;line-height:24px;font-size:16px;'>If you didn't create an account, you can safely ignore this email.</p><div style='text-align:left;font-size:16px;margin-top:24px;'>- URLShortener Team</div><div style='padding:10px;border-radius:0 0 5px 5px;margin-top:20px;font-size:12px;line-height:18px;'>UrlShortener is a hobby project by Akhilesh Garg</div>"
+}
+
+func GenerateAccountDeletedEmailBody(name string, restoreLink string, graceDays int64) string {
+	return "<div style='font-family:Arial,sans-serif;margin:0;padding:0;background: linear-gradient(to bottom, #f7f8f9, #ffffff)!important;max-width:600px;margin:20px auto;padding:20px;background-color:#fff;border-radius:5px;box-shadow:0 0 10px rgba(0,0,0,0.1);color:#333;text-align:center;'><p style='font-size:16px;text-align:left'>Dear " + name + ",</p><p style='text-align:left;line-height:24px;font-size:16px;'>Your UrlShortener account has been deleted and you have been logged out of every device. Your account and links will be permanently removed in " + strconv.FormatInt(graceDays, 10) + " days.</p><p style='font-size:16px;text-align:left;margin-top:24px;'>Changed your mind? You can restore your account until then by clicking the button below:</p><a href='" + restoreLink + "' style='display:inline-block;margin:24px 0;padding:12px 24px;background-color:#15c;color:#fff;text-decoration:none;border-radius:5px;font-size:16px;'>Restore Account</a><p style='text-align:left;margin-top:24px;line-height:24px;font-size:16px;'>If you didn't delete your account, restore it right away & change your password.</p><div style='text-align:left;font-size:16px;margin-top:24px;'>- URLShortener Team</div><div style='padding:10px;border-radius:0 0 5px 5px;margin-top:20px;font-size:12px;line-height:18px;'>UrlShortener is a hobby project by Akhilesh Garg</div>"
+}
+
+func GenerateDataExportReadyEmailBody(name string, downloadLink string, validityHours int64) string {
+	return "<div style='font-family:Arial,sans-serif;margin:0;padding:0;background: linear-gradient(to bottom, #f7f8f9, #ffffff)!important;max-width:600px;margin:20px auto;padding:20px;background-color:#fff;border-radius:5px;box-shadow:0 0 10px rgba(0,0,0,0.1);color:#333;text-align:center;'><p style='font-size:16px;text-align:left'>Dear " + name + ",</p><p style='text-align:left;line-height:24px;font-size:16px;'>The copy of your UrlShortener data you requested is ready. Click the button below to download it. The link is valid for " + strconv.FormatInt(validityHours, 10) + " hours.</p><a href='" + downloadLink + "' style='display:inline-block;margin:24px 0;padding:12px 24px;background-color:#15c;color:#fff;text-decoration:none;border-radius:5px;font-size:16px;'>Download Your Data</a><p style='text-align:left;margin-top:24px;line-height:24px;font-size:16px;'>The export contains personal information, don't share this link with anyone. If you didn't request it, change your password & contact us via our support site.</p><div style='text-align:left;font-size:16px;margin-top:24px;'>- URLShortener Team</div><div style='padding:10px;border-radius:0 0 5px 5px;margin-top:20px;font-size:12px;line-height:18px;'>UrlShortener is a hobby project by Akhilesh Garg</div>"
+}
+
+func GenerateEmailChangeConfirmEmailBody(name string, confirmLink string, validityHours int64) string {
+	return "<div style='font-family:Arial,sans-serif;margin:0;padding:0;background: linear-gradient(to bottom, #f7f8f9, #ffffff)!important;max-width:600px;margin:20px auto;padding:20px;background-color:#fff;border-radius:5px;box-shadow:0 0 10px rgba(0,0,0,0.1);color:#333;text-align:center;'><p style='font-size:16px;text-align:left'>Dear " + name + ",</p><p style='text-align:left;line-height:24px;font-size:16px;'>You asked to use this address for your UrlShortener account. Click the button below to confirm it. The link is valid for " + strconv.FormatInt(validityHours, 10) + " hours.</p><a href='" + confirmLink + "' style='display:inline-block;margin:24px 0;padding:12px 24px;background-color:#15c;color:#fff;text-decoration:none;border-radius:5px;font-size:16px;'>Confirm Email Change</a><p style='text-align:left;margin-top:24px;line-height:24px;font-size:16px;'>Once confirmed, you'll be logged out of every device and have to log in using this address. If you didn't ask for this change, ignore this email.</p><div style='text-align:left;font-size:16px;margin-top:24px;'>- URLShortener Team</div><div style='padding:10px;border-radius:0 0 5px 5px;margin-top:20px;font-size:12px;line-height:18px;'>UrlShortener is a hobby project by Akhilesh Garg</div>"
+}
+
+func GenerateEmailChangeRequestedEmailBody(name string, newEmail string, cancelLink string) string {
+	return "<div style='font-family:Arial,sans-serif;margin:0;padding:0;background: linear-gradient(to bottom, #f7f8f9, #ffffff)!important;max-width:600px;margin:20px auto;padding:20px;background-color:#fff;border-radius:5px;box-shadow:0 0 10px rgba(0,0,0,0.1);color:#333;text-align:center;'><p style='font-size:16px;text-align:left'>Dear " + name + ",</p><p style='text-align:left;line-height:24px;font-size:16px;'>A change of the email of your UrlShortener account to <strong>" + newEmail + "</strong> was requested. It will be applied once confirmed from the new address.</p><p style='text-align:left;line-height:24px;font-size:16px;'>If you didn't request it, click the button below to cancel it, then change your password.</p><a href='" + cancelLink + "' style='display:inline-block;margin:24px 0;padding:12px 24px;background-color:#15c;color:#fff;text-decoration:none;border-radius:5px;font-size:16px;'>Cancel Email Change</a><div style='text-align:left;font-size:16px;margin-top:24px;'>- URLShortener Team</div><div style='padding:10px;border-radius:0 0 5px 5px;margin-top:20px;font-size:12px;line-height:18px;'>UrlShortener is a hobby project by Akhilesh Garg</div>"
+}
+
+func GenerateEmailChangedEmailBody(name string, newEmail string) string {
+	return "<div style='font-family:Arial,sans-serif;margin:0;padding:0;background: linear-gradient(to bottom, #f7f8f9, #ffffff)!important;max-width:600px;margin:20px auto;padding:20px;background-color:#fff;border-radius:5px;box-shadow:0 0 10px rgba(0,0,0,0.1);color:#333;text-align:center;'><p style='font-size:16px;text-align:left'>Dear " + name + ",</p><p style='text-align:left;line-height:24px;font-size:16px;'>The email of your UrlShortener account was changed to <strong>" + newEmail + "</strong> and you have been logged out of every device. This address won't receive emails about your account anymore.</p><p style='text-align:left;margin-top:24px;line-height:24px;font-size:16px;'>If you didn't make this change, contact us immediately via our support site.</p><div style='text-align:left;font-size:16px;margin-top:24px;'>- URLShortener Team</div><div style='padding:10px;border-radius:0 0 5px 5px;margin-top:20px;font-size:12px;line-height:18px;'>UrlShortener is a hobby project by Akhilesh Garg</div>"
+}
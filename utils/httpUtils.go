@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/akgarg0472/urlshortener-auth-service/model"
+)
+
+// ExtractAuthToken returns the bearer token from the Authorization header or the auth_token cookie
+func ExtractAuthToken(httpRequest *http.Request) string {
+	authorizationHeader := httpRequest.Header.Get("Authorization")
+
+	if strings.HasPrefix(authorizationHeader, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(authorizationHeader, "Bearer "))
+	}
+
+	cookie, err := httpRequest.Cookie("auth_token")
+
+	if err != nil {
+		return ""
+	}
+
+	return cookie.Value
+}
+
+var (
+	trustedProxies     []*net.IPNet
+	trustedProxiesOnce sync.Once
+)
+
+// ExtractClientInfo returns the IP address and user agent of the client which sent the request. The IP address is
+// taken from the X-Forwarded-For or X-Real-IP headers only when the request comes from one of the TRUSTED_PROXIES
+func ExtractClientInfo(httpRequest *http.Request) model.ClientInfo {
+	return model.ClientInfo{
+		IpAddress: extractClientIp(httpRequest),
+		UserAgent: httpRequest.UserAgent(),
+	}
+}
+
+// function to return the address of the client. The forwarded headers can be set by anyone, so they are only honoured
+// when the peer is a trusted proxy, and the client is the right-most X-Forwarded-For hop which isn't a trusted proxy
+func extractClientIp(httpRequest *http.Request) string {
+	remoteIp, _, err := net.SplitHostPort(httpRequest.RemoteAddr)
+
+	if err != nil {
+		remoteIp = httpRequest.RemoteAddr
+	}
+
+	if !isTrustedProxy(remoteIp) {
+		return remoteIp
+	}
+
+	forwardedFor := strings.Join(httpRequest.Header.Values("X-Forwarded-For"), ",")
+
+	if forwardedFor == "" {
+		if realIp := strings.TrimSpace(httpRequest.Header.Get("X-Real-IP")); net.ParseIP(realIp) != nil {
+			return realIp
+		}
+		return remoteIp
+	}
+
+	clientIp := remoteIp
+	hops := strings.Split(forwardedFor, ",")
+
+	// hops left of an invalid one can't be trusted, the last valid hop is used instead
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+
+		if net.ParseIP(hop) == nil {
+			break
+		}
+
+		clientIp = hop
+
+		if !isTrustedProxy(hop) {
+			break
+		}
+	}
+
+	return clientIp
+}
+
+func isTrustedProxy(ipAddress string) bool {
+	trustedProxiesOnce.Do(func() {
+		trustedProxies = parseTrustedProxies(GetEnvVariable("TRUSTED_PROXIES", ""))
+	})
+
+	ip := net.ParseIP(ipAddress)
+
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// function to parse the comma separated list of CIDRs or IP addresses of the proxies. Invalid entries are ignored
+func parseTrustedProxies(value string) []*net.IPNet {
+	var networks []*net.IPNet
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil {
+				if ip.To4() != nil {
+					entry += "/32"
+				} else {
+					entry += "/128"
+				}
+			}
+		}
+
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			networks = append(networks, network)
+		}
+	}
+
+	return networks
+}
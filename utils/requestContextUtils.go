@@ -4,21 +4,53 @@ type contextKey string
 
 // RequestContextKeys holds the context key constants
 var RequestContextKeys = struct {
-	LoginRequestKey          contextKey
-	SignupRequestKey         contextKey
-	LogoutRequestKey         contextKey
-	ValidateTokenRequestKey  contextKey
-	ForgotPasswordRequestKey contextKey
-	OAuthCallbackRequestKey  contextKey
-	ResetPasswordRequestKey  contextKey
-	VerifyAdminRequestKey    contextKey
+	LoginRequestKey             contextKey
+	SignupRequestKey            contextKey
+	LogoutRequestKey            contextKey
+	ValidateTokenRequestKey     contextKey
+	ForgotPasswordRequestKey    contextKey
+	OAuthCallbackRequestKey     contextKey
+	ResetPasswordRequestKey     contextKey
+	VerifyAdminRequestKey       contextKey
+	RefreshTokenRequestKey      contextKey
+	AuthClaimsKey               contextKey
+	AddSigningKeyRequestKey     contextKey
+	RetireSigningKeyRequestKey  contextKey
+	IntrospectionClientIdKey    contextKey
+	TotpCodeRequestKey          contextKey
+	MfaLoginRequestKey          contextKey
+	LoginOtpRequestKey          contextKey
+	LoginOtpVerifyRequestKey    contextKey
+	MagicLinkRequestKey         contextKey
+	ResendVerificationKey       contextKey
+	ChangePasswordRequestKey    contextKey
+	LinkOAuthIdentityRequestKey contextKey
+	UpdateProfileRequestKey     contextKey
+	DeleteAccountRequestKey     contextKey
+	ChangeEmailRequestKey       contextKey
 }{
-	LoginRequestKey:          "loginRequest",
-	SignupRequestKey:         "signupRequest",
-	LogoutRequestKey:         "logoutRequest",
-	ValidateTokenRequestKey:  "validateTokenRequest",
-	ForgotPasswordRequestKey: "forgotPasswordRequest",
-	OAuthCallbackRequestKey:  "oAuthCallbackRequest",
-	ResetPasswordRequestKey:  "resetPasswordRequest",
-	VerifyAdminRequestKey:    "verifyAdminRequest",
+	LoginRequestKey:             "loginRequest",
+	SignupRequestKey:            "signupRequest",
+	LogoutRequestKey:            "logoutRequest",
+	ValidateTokenRequestKey:     "validateTokenRequest",
+	ForgotPasswordRequestKey:    "forgotPasswordRequest",
+	OAuthCallbackRequestKey:     "oAuthCallbackRequest",
+	ResetPasswordRequestKey:     "resetPasswordRequest",
+	VerifyAdminRequestKey:       "verifyAdminRequest",
+	RefreshTokenRequestKey:      "refreshTokenRequest",
+	AuthClaimsKey:               "authClaims",
+	AddSigningKeyRequestKey:     "addSigningKeyRequest",
+	RetireSigningKeyRequestKey:  "retireSigningKeyRequest",
+	IntrospectionClientIdKey:    "introspectionClientId",
+	TotpCodeRequestKey:          "totpCodeRequest",
+	MfaLoginRequestKey:          "mfaLoginRequest",
+	LoginOtpRequestKey:          "loginOtpRequest",
+	LoginOtpVerifyRequestKey:    "loginOtpVerifyRequest",
+	MagicLinkRequestKey:         "magicLinkRequest",
+	ResendVerificationKey:       "resendVerificationEmailRequest",
+	ChangePasswordRequestKey:    "changePasswordRequest",
+	LinkOAuthIdentityRequestKey: "linkOAuthIdentityRequest",
+	UpdateProfileRequestKey:     "updateProfileRequest",
+	DeleteAccountRequestKey:     "deleteAccountRequest",
+	ChangeEmailRequestKey:       "changeEmailRequest",
 }
@@ -1,19 +1,89 @@
 package utils
 
 import (
+	"net/url"
 	"strings"
 )
 
 func GenerateForgotPasswordTokenRedirectUrl(email string, token string) string {
 	frontendBaseUrl := GetEnvVariable("FRONTEND_BASE_DOMAIN", "http://127.0.0.1:3000/")
 	resetPasswordPageUrl := GetEnvVariable("FRONTEND_RESET_PASSWORD_PAGE_URL", "reset-password")
-	return EnsureTrailingSlash(frontendBaseUrl) + resetPasswordPageUrl + "?token=" + token + "&email=" + email
+	return EnsureTrailingSlash(frontendBaseUrl) + resetPasswordPageUrl + "?token=" + url.QueryEscape(token) + "&email=" + url.QueryEscape(email)
 }
 
 func GenerateForgotPasswordLink(email string, forgotPasswordToken string) string {
 	backendBaseUrl := GetEnvVariable("BACKEND_BASE_DOMAIN", "http://localhost:8765/")
 	backendResetPasswordUrl := GetEnvVariable("BACKEND_RESET_PASSWORD_URL", "auth/v1/reset-password")
-	return EnsureTrailingSlash(backendBaseUrl) + backendResetPasswordUrl + "?email=" + email + "&token=" + forgotPasswordToken
+	return EnsureTrailingSlash(backendBaseUrl) + backendResetPasswordUrl + "?email=" + url.QueryEscape(email) + "&token=" + url.QueryEscape(forgotPasswordToken)
+}
+
+func GenerateMagicLink(token string) string {
+	backendBaseUrl := GetEnvVariable("BACKEND_BASE_DOMAIN", "http://localhost:8765/")
+	backendMagicLinkUrl := GetEnvVariable("BACKEND_MAGIC_LINK_URL", "api/v1/auth/login/magic-link/verify")
+	return EnsureTrailingSlash(backendBaseUrl) + backendMagicLinkUrl + "?token=" + url.QueryEscape(token)
+}
+
+// GenerateMagicLinkRedirectUrl returns the frontend magic link page URL carrying the parameters in the fragment, which
+// browsers never send to servers
+func GenerateMagicLinkRedirectUrl(fragmentParams url.Values) string {
+	frontendBaseUrl := GetEnvVariable("FRONTEND_BASE_DOMAIN", "http://127.0.0.1:3000/")
+	magicLinkPageUrl := GetEnvVariable("FRONTEND_MAGIC_LINK_PAGE_URL", "magic-link")
+	return EnsureTrailingSlash(frontendBaseUrl) + magicLinkPageUrl + "#" + fragmentParams.Encode()
+}
+
+func GenerateEmailVerificationLink(token string) string {
+	backendBaseUrl := GetEnvVariable("BACKEND_BASE_DOMAIN", "http://localhost:8765/")
+	backendVerifyEmailUrl := GetEnvVariable("BACKEND_VERIFY_EMAIL_URL", "api/v1/auth/verify-email")
+	return EnsureTrailingSlash(backendBaseUrl) + backendVerifyEmailUrl + "?token=" + url.QueryEscape(token)
+}
+
+func GenerateEmailVerifiedRedirectUrl() string {
+	frontendBaseUrl := GetEnvVariable("FRONTEND_BASE_DOMAIN", "http://127.0.0.1:3000/")
+	return EnsureTrailingSlash(frontendBaseUrl) + GetEnvVariable("FRONTEND_EMAIL_VERIFIED_PAGE_URL", "email-verified")
+}
+
+func GenerateAccountRestoreLink(token string) string {
+	backendBaseUrl := GetEnvVariable("BACKEND_BASE_DOMAIN", "http://localhost:8765/")
+	backendRestoreAccountUrl := GetEnvVariable("BACKEND_RESTORE_ACCOUNT_URL", "api/v1/users/restore")
+	return EnsureTrailingSlash(backendBaseUrl) + backendRestoreAccountUrl + "?token=" + url.QueryEscape(token)
+}
+
+func GenerateAccountRestoredRedirectUrl() string {
+	frontendBaseUrl := GetEnvVariable("FRONTEND_BASE_DOMAIN", "http://127.0.0.1:3000/")
+	return EnsureTrailingSlash(frontendBaseUrl) + GetEnvVariable("FRONTEND_ACCOUNT_RESTORED_PAGE_URL", "account-restored")
+}
+
+func GenerateEmailChangeConfirmLink(token string) string {
+	backendBaseUrl := GetEnvVariable("BACKEND_BASE_DOMAIN", "http://localhost:8765/")
+	backendConfirmEmailChangeUrl := GetEnvVariable("BACKEND_CONFIRM_EMAIL_CHANGE_URL", "api/v1/users/email/confirm")
+	return EnsureTrailingSlash(backendBaseUrl) + backendConfirmEmailChangeUrl + "?token=" + url.QueryEscape(token)
+}
+
+func GenerateEmailChangeCancelLink(token string) string {
+	backendBaseUrl := GetEnvVariable("BACKEND_BASE_DOMAIN", "http://localhost:8765/")
+	backendCancelEmailChangeUrl := GetEnvVariable("BACKEND_CANCEL_EMAIL_CHANGE_URL", "api/v1/users/email/cancel")
+	return EnsureTrailingSlash(backendBaseUrl) + backendCancelEmailChangeUrl + "?token=" + url.QueryEscape(token)
+}
+
+func GenerateEmailChangedRedirectUrl() string {
+	frontendBaseUrl := GetEnvVariable("FRONTEND_BASE_DOMAIN", "http://127.0.0.1:3000/")
+	return EnsureTrailingSlash(frontendBaseUrl) + GetEnvVariable("FRONTEND_EMAIL_CHANGED_PAGE_URL", "email-changed")
+}
+
+func GenerateEmailChangeCancelledRedirectUrl() string {
+	frontendBaseUrl := GetEnvVariable("FRONTEND_BASE_DOMAIN", "http://127.0.0.1:3000/")
+	return EnsureTrailingSlash(frontendBaseUrl) + GetEnvVariable("FRONTEND_EMAIL_CHANGE_CANCELLED_PAGE_URL", "email-change-cancelled")
+}
+
+func GenerateDataExportDownloadLink(token string) string {
+	backendBaseUrl := GetEnvVariable("BACKEND_BASE_DOMAIN", "http://localhost:8765/")
+	backendDataExportUrl := GetEnvVariable("BACKEND_DATA_EXPORT_DOWNLOAD_URL", "api/v1/users/export/download")
+	return EnsureTrailingSlash(backendBaseUrl) + backendDataExportUrl + "?token=" + url.QueryEscape(token)
+}
+
+func GenerateDashboardUrl() string {
+	frontendBaseUrl := GetEnvVariable("FRONTEND_BASE_DOMAIN", "http://127.0.0.1:3000/")
+	return EnsureTrailingSlash(frontendBaseUrl) + GetEnvVariable("FRONTEND_DASHBOARD_PAGE_URL", "dashboard")
 }
 
 func GetStringOrNil(s *string) string {
@@ -38,3 +108,14 @@ func EnsureTrailingSlash(url string) string {
 	}
 	return url
 }
+
+// HasAdminScope checks if any of the comma separated scopes is an admin scope
+func HasAdminScope(scopes string) bool {
+	for _, scope := range strings.Split(scopes, ",") {
+		if strings.Contains(strings.ToLower(scope), "admin") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,12 +1,23 @@
 package utils
 
 import (
+	"regexp"
+
 	AuthModels "github.com/akgarg0472/urlshortener-auth-service/model"
 	Validator "github.com/go-playground/validator/v10"
 )
 
 var validator = Validator.New()
 
+// zipcodes of most countries are made of letters and digits, optionally separated by a space or hyphen
+var zipcodeRegex = regexp.MustCompile(`^[A-Za-z0-9]+([ -][A-Za-z0-9]+)*$`)
+
+func init() {
+	_ = validator.RegisterValidation("zipcode", func(fieldLevel Validator.FieldLevel) bool {
+		return zipcodeRegex.MatchString(fieldLevel.Field().String())
+	})
+}
+
 func ValidateRequestFields(request interface{}) map[string]string {
 	err := validator.Struct(request)
 